@@ -14,4 +14,5 @@ require (
 	github.com/pkg/errors v0.9.1
 	github.com/spf13/viper v1.7.1
 	github.com/streadway/amqp v1.0.0
+	golang.org/x/image v0.0.0-20210628002857-a66eb6448b8d
 )
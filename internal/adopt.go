@@ -0,0 +1,92 @@
+package internal
+
+import (
+	"html"
+	"sort"
+)
+
+// maxAdoptedArtists tagged on a mood adopted from an existing playlist
+const maxAdoptedArtists = 20
+
+// AdoptPlaylist creates a mood managing an existing playlist of the user, tagged with the playlist's most frequent artists.
+// The mood takes over the playlist as it is, so no playlist is created for it.
+func (s *MoodService) AdoptPlaylist(playlistID string, mood *Mood, token *SpotifyToken) (*Mood, error) {
+	playlist, err := s.spotify.GetPlaylist(token, playlistID)
+	if err != nil {
+		return nil, err
+	}
+	if playlist.Owner.ID != token.User.SpotifyID {
+		return nil, ErrNotOwner
+	}
+
+	// A trashed mood still owns its playlist until the sweep deletes it, so it cannot be adopted in the meantime
+	if _, err := s.r.FindByPlaylistID(playlist.ID); err == nil {
+		return nil, ErrAdopted
+	} else if err != ErrNotFound {
+		return nil, err
+	}
+
+	mood.PlaylistID = playlist.ID
+	mood.Description = html.UnescapeString(playlist.Description)
+	mood.Public = playlist.Public
+	mood.Collaborative = playlist.Collaborative
+	if mood.Name == "" {
+		mood.Name = playlist.Name
+	}
+	if mood.Color == "" {
+		mood.Color = suggestionColors[0]
+	}
+
+	tracks, err := s.GetPlaylistTracks(mood, token)
+	if err != nil {
+		return nil, err
+	}
+
+	mood.Tags = make([]Tag, 0)
+	for _, id := range frequentArtists(tracks, maxAdoptedArtists) {
+		mood.Tags = append(mood.Tags, Tag{Type: TagTypeArtist, SpotifyID: id})
+	}
+	mood.TrackCount = len(tracks)
+	for _, track := range tracks {
+		mood.DurationMs += track.DurationMs
+	}
+
+	if mood.Position, err = s.r.NextPosition(&token.User); err != nil {
+		return nil, err
+	}
+
+	mood.User = token.User
+	if err := s.r.Save(mood); err != nil {
+		return nil, err
+	}
+	s.record(mood, AuditActionCreate, AuditSourceAPI, &token.User, diffAudit(auditState{}, mood.auditState()))
+
+	return mood, nil
+}
+
+// frequentArtists of the given tracks, up to the given limit, from the most frequent
+func frequentArtists(tracks []*SpotifyTrack, limit int) []string {
+	counts := make(map[string]int)
+	ids := make([]string, 0)
+	for _, track := range tracks {
+		for _, artist := range track.Artists {
+			if artist.ID == "" {
+				continue
+			}
+			if counts[artist.ID] == 0 {
+				ids = append(ids, artist.ID)
+			}
+			counts[artist.ID]++
+		}
+	}
+
+	// Stable sort keeps artists of equal frequency in playlist order
+	sort.SliceStable(ids, func(i, j int) bool {
+		return counts[ids[i]] > counts[ids[j]]
+	})
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+
+	return ids
+}
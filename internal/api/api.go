@@ -39,6 +39,7 @@ func InitRoutes(e *echo.Echo, opts Options) {
 	newUser(opts.Services).Routes(base)
 	newMood(opts.Services).Routes(base)
 	newSpotify(opts.Services).Routes(base)
+	newShared(opts.Services).Routes(base)
 }
 
 func notFound(c echo.Context, resource string) error {
@@ -29,7 +29,12 @@ func generateToken(opts TokenOptions) (string, error) {
 }
 
 func useAuthMiddleware(g *echo.Group, opts Options) {
-	g.Use(middleware.JWT([]byte(viper.GetString("app.secret"))), authUser(opts))
+	g.Use(authMiddleware(opts)...)
+}
+
+// authMiddleware for routes which require an authenticated user
+func authMiddleware(opts Options) []echo.MiddlewareFunc {
+	return []echo.MiddlewareFunc{middleware.JWT([]byte(viper.GetString("app.secret"))), authUser(opts)}
 }
 
 // authUser middleware to verify an existing user for a token
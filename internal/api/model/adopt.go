@@ -0,0 +1,30 @@
+package model
+
+import (
+	"strings"
+
+	"github.com/flexicon/spotimoods-go/internal"
+)
+
+// AdoptPayload for creating a Mood from an existing spotify playlist
+type AdoptPayload struct {
+	PlaylistID string `json:"playlist_id" validate:"required,alphanum,lte=64"`
+	// Name and Color are optional, the playlist name and a default color are used otherwise
+	Name  string `json:"name" validate:"lte=64"`
+	Color string `json:"color" validate:"omitempty,hexcolor"`
+}
+
+// Validate struct fields
+func (p *AdoptPayload) Validate() error {
+	p.PlaylistID = strings.TrimSpace(p.PlaylistID)
+	p.Name = strings.TrimSpace(p.Name)
+	return validate.Struct(p)
+}
+
+// Mood to adopt the playlist into
+func (p *AdoptPayload) Mood() *internal.Mood {
+	return &internal.Mood{
+		Name:  p.Name,
+		Color: p.Color,
+	}
+}
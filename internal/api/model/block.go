@@ -0,0 +1,16 @@
+package model
+
+import "strings"
+
+// BlockPayload for keeping an artist or track out of playlists
+type BlockPayload struct {
+	Type string `json:"type" validate:"required,oneof=artist track"`
+	// SpotifyID is not named ID, so it never gets bound from the mood id path param
+	SpotifyID string `json:"id" validate:"required,lte=128"`
+}
+
+// Validate struct fields
+func (p *BlockPayload) Validate() error {
+	p.SpotifyID = strings.TrimSpace(p.SpotifyID)
+	return validate.Struct(p)
+}
@@ -0,0 +1,42 @@
+package model
+
+import (
+	"fmt"
+
+	"github.com/flexicon/spotimoods-go/internal"
+)
+
+// maxTempo accepted as the bound of a tempo range, in BPM
+const maxTempo = 250
+
+// validateFeatures checks that every given range falls within the scale of its audio feature and isn't inverted
+func validateFeatures(f *internal.MoodFeatures) error {
+	if f == nil {
+		return nil
+	}
+
+	ranges := []struct {
+		name  string
+		r     internal.FeatureRange
+		limit float64
+	}{
+		{"energy", f.Energy, 1},
+		{"valence", f.Valence, 1},
+		{"danceability", f.Danceability, 1},
+		{"acousticness", f.Acousticness, 1},
+		{"tempo", f.Tempo, maxTempo},
+	}
+
+	for _, fr := range ranges {
+		for _, bound := range []*float64{fr.r.Min, fr.r.Max} {
+			if bound != nil && (*bound < 0 || *bound > fr.limit) {
+				return fmt.Errorf("%s range must be within 0 and %v", fr.name, fr.limit)
+			}
+		}
+		if fr.r.Min != nil && fr.r.Max != nil && *fr.r.Min > *fr.r.Max {
+			return fmt.Errorf("%s range min must not be greater than max", fr.name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"time"
+
+	"github.com/flexicon/spotimoods-go/internal"
+)
+
+// HistoryQuery for requesting a page of listening history
+type HistoryQuery struct {
+	Limit int `query:"limit" validate:"omitempty,min=1,max=50"`
+	// Before is the cursor to continue listing from, as returned in the previous page
+	Before time.Time `query:"before"`
+}
+
+// Validate struct fields
+func (q *HistoryQuery) Validate() error {
+	if q.Limit == 0 {
+		q.Limit = defaultPageLimit
+	}
+	return validate.Struct(q)
+}
+
+// HistoryResponse with a page of plays and the cursor for the following page, if there is one
+type HistoryResponse struct {
+	Items []*internal.Play `json:"items"`
+	Next  *time.Time       `json:"next"`
+}
+
+// NewHistoryResponse from the given page of plays requested with the given limit
+func NewHistoryResponse(plays []*internal.Play, limit int) *HistoryResponse {
+	resp := &HistoryResponse{Items: plays}
+	if len(plays) > 0 && len(plays) == limit {
+		resp.Next = &plays[len(plays)-1].PlayedAt
+	}
+
+	return resp
+}
+
+// PlayCountsQuery for requesting daily play counts
+type PlayCountsQuery struct {
+	Days int `query:"days" validate:"omitempty,min=1,max=365"`
+}
+
+// Validate struct fields
+func (q *PlayCountsQuery) Validate() error {
+	if q.Days == 0 {
+		q.Days = 30
+	}
+	return validate.Struct(q)
+}
@@ -0,0 +1,101 @@
+package model
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+
+	"github.com/flexicon/spotimoods-go/internal"
+)
+
+// MoodsQuery for requesting a page of the user's moods
+type MoodsQuery struct {
+	Limit int    `query:"limit" validate:"omitempty,min=1,max=50"`
+	Sort  string `query:"sort" validate:"omitempty,oneof=position name created_at updated_at"`
+	Order string `query:"order" validate:"omitempty,oneof=asc desc"`
+	// Cursor to continue listing from, as returned in the previous page
+	Cursor      string `query:"cursor"`
+	HasPlaylist *bool  `query:"has_playlist"`
+	Color       string `query:"color" validate:"omitempty,hexcolor"`
+	Name        string `query:"name" validate:"lte=64"`
+	Artist      string `query:"artist" validate:"lte=128"`
+}
+
+// Validate struct fields
+func (q *MoodsQuery) Validate() error {
+	if q.Limit == 0 {
+		q.Limit = defaultPageLimit
+	}
+	if q.Sort == "" {
+		q.Sort = internal.MoodSortPosition
+	}
+	return validate.Struct(q)
+}
+
+// Query to list the moods by, failing when the cursor is invalid or was issued for another sort order
+func (q *MoodsQuery) Query() (internal.MoodQuery, error) {
+	query := internal.MoodQuery{
+		Sort:        q.Sort,
+		Desc:        q.Order == "desc",
+		Limit:       q.Limit,
+		HasPlaylist: q.HasPlaylist,
+		Color:       q.Color,
+		Name:        q.Name,
+		ArtistID:    q.Artist,
+	}
+	if q.Cursor == "" {
+		return query, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(q.Cursor)
+	if err != nil {
+		return query, errors.New("invalid cursor")
+	}
+	var cursor internal.MoodCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil {
+		return query, errors.New("invalid cursor")
+	}
+	if cursor.Sort != query.Sort || cursor.Desc != query.Desc {
+		return query, errors.New("cursor does not match the requested sort order")
+	}
+	query.After = &cursor
+
+	return query, nil
+}
+
+// MoodsResponse with a page of moods and the cursor for the following page, if there is one
+type MoodsResponse struct {
+	Items []*internal.Mood `json:"items"`
+	Next  *string          `json:"next"`
+}
+
+// NewMoodsResponse from the given page of moods and the cursor of the following one
+func NewMoodsResponse(moods []*internal.Mood, next *internal.MoodCursor) (*MoodsResponse, error) {
+	resp := &MoodsResponse{Items: moods}
+	if resp.Items == nil {
+		resp.Items = []*internal.Mood{}
+	}
+	if next == nil {
+		return resp, nil
+	}
+
+	raw, err := json.Marshal(next)
+	if err != nil {
+		return nil, err
+	}
+	cursor := base64.RawURLEncoding.EncodeToString(raw)
+	resp.Next = &cursor
+
+	return resp, nil
+}
+
+// OrderPayload for arranging all of the user's moods
+type OrderPayload struct {
+	// IDs of every mood of the user, in their new order
+	IDs []uint `json:"ids" validate:"required,max=1000,dive,required"`
+}
+
+// Validate struct fields
+func (p *OrderPayload) Validate() error {
+	return validate.Struct(p)
+}
@@ -1,27 +1,135 @@
 package model
 
-import "strings"
+import (
+	"strings"
+
+	"github.com/flexicon/spotimoods-go/internal"
+)
 
 // MoodPayload for creating a new Mood
 type MoodPayload struct {
 	Name  string `json:"name,omitempty" validate:"required,lte=64"`
 	Color string `json:"color,omitempty" validate:"required,hexcolor"`
+	// Description template, which may use the {name}, {artists} and {date} placeholders
+	Description   string `json:"description,omitempty" validate:"lte=300"`
+	CoverInitials bool   `json:"cover_initials,omitempty"`
+	// Public defaults to true, unless the playlist is collaborative
+	Public        *bool                  `json:"public,omitempty"`
+	Collaborative bool                   `json:"collaborative,omitempty"`
+	Tags          []TagPayload           `json:"tags,omitempty" validate:"lte=100,dive"`
+	Features      *internal.MoodFeatures `json:"features,omitempty"`
 }
 
 // Validate struct fields
 func (p *MoodPayload) Validate() error {
 	p.Name = strings.TrimSpace(p.Name)
-	return validate.Struct(p)
+	if err := validate.Struct(p); err != nil {
+		return err
+	}
+	if p.Collaborative && p.Public != nil && *p.Public {
+		return internal.ErrPublicCollaborative
+	}
+	return validateFeatures(p.Features)
+}
+
+// Mood to create from the payload
+func (p *MoodPayload) Mood() *internal.Mood {
+	mood := &internal.Mood{
+		Name:          p.Name,
+		Color:         p.Color,
+		Description:   p.Description,
+		CoverInitials: p.CoverInitials,
+		Public:        !p.Collaborative,
+		Collaborative: p.Collaborative,
+		Tags:          toTags(p.Tags),
+	}
+	if p.Public != nil {
+		mood.Public = *p.Public
+	}
+	if p.Features != nil {
+		mood.Features = *p.Features
+	}
+
+	return mood
 }
 
 // MoodChanges for updating a Mood
 type MoodChanges struct {
 	Name  string `json:"name" validate:"lte=64"`
 	Color string `json:"color" validate:"hexcolor"`
+	// Description replaces the description template when given, an empty one removes it
+	Description   *string `json:"description" validate:"omitempty,lte=300"`
+	CoverInitials *bool   `json:"cover_initials"`
+	Public        *bool   `json:"public"`
+	Collaborative *bool   `json:"collaborative"`
+	// Tags replace all of the mood's tags when given
+	Tags *[]TagPayload `json:"tags" validate:"omitempty,lte=100,dive"`
+	// Features replace all of the mood's feature ranges when given
+	Features *internal.MoodFeatures `json:"features"`
+	Pinned   *bool                  `json:"pinned"`
 }
 
 // Validate struct fields
 func (p *MoodChanges) Validate() error {
 	p.Name = strings.TrimSpace(p.Name)
-	return validate.Struct(p)
+	if err := validate.Struct(p); err != nil {
+		return err
+	}
+	return validateFeatures(p.Features)
+}
+
+// Changes to apply to the mood, leaving out empty fields
+func (p *MoodChanges) Changes() internal.MoodChanges {
+	changes := internal.MoodChanges{
+		Description:   p.Description,
+		CoverInitials: p.CoverInitials,
+		Public:        p.Public,
+		Collaborative: p.Collaborative,
+		Features:      p.Features,
+		Pinned:        p.Pinned,
+	}
+	// Turning a mood collaborative makes it private, unless visibility was given as well, the same as on creation
+	if p.Collaborative != nil && *p.Collaborative && p.Public == nil {
+		public := false
+		changes.Public = &public
+	}
+	if p.Name != "" {
+		changes.Name = &p.Name
+	}
+	if p.Color != "" {
+		changes.Color = &p.Color
+	}
+	if p.Tags != nil {
+		changes.Tags = toTags(*p.Tags)
+	}
+
+	return changes
+}
+
+// TagPayload for tagging a Mood with a spotify artist, track, album or genre
+type TagPayload struct {
+	Type string `json:"type" validate:"required,oneof=artist track album genre"`
+	ID   string `json:"id" validate:"required,lte=128"`
+}
+
+// toTags converts the given payloads into a list of unique tags
+func toTags(payloads []TagPayload) []internal.Tag {
+	tags := make([]internal.Tag, 0, len(payloads))
+	seen := make(map[TagPayload]bool)
+
+	for _, p := range payloads {
+		p.ID = strings.TrimSpace(p.ID)
+		if p.Type == internal.TagTypeGenre {
+			// Spotify genres are always lower case
+			p.ID = strings.ToLower(p.ID)
+		}
+		if seen[p] {
+			continue
+		}
+		seen[p] = true
+
+		tags = append(tags, internal.Tag{Type: p.Type, SpotifyID: p.ID})
+	}
+
+	return tags
 }
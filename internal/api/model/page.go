@@ -0,0 +1,75 @@
+package model
+
+import "github.com/flexicon/spotimoods-go/internal"
+
+// defaultPageLimit used when no limit is requested
+const defaultPageLimit = 20
+
+// PageQuery for requesting a page of a paginated listing
+type PageQuery struct {
+	Limit  int `query:"limit" validate:"omitempty,min=1,max=50"`
+	Offset int `query:"offset" validate:"omitempty,min=0,max=1000"`
+}
+
+// Validate struct fields
+func (q *PageQuery) Validate() error {
+	return validate.Struct(q)
+}
+
+// Options to request the page from spotify with
+func (q *PageQuery) Options() internal.SpotifyPageOptions {
+	limit := q.Limit
+	if limit == 0 {
+		limit = defaultPageLimit
+	}
+
+	return internal.SpotifyPageOptions{
+		Limit:  limit,
+		Offset: q.Offset,
+	}
+}
+
+// TopQuery for requesting a page of the user's top items
+type TopQuery struct {
+	PageQuery
+	TimeRange string `query:"time_range" validate:"omitempty,oneof=short_term medium_term long_term"`
+}
+
+// Validate struct fields
+func (q *TopQuery) Validate() error {
+	return validate.Struct(q)
+}
+
+// Options to request the page from spotify with
+func (q *TopQuery) Options() internal.SpotifyPageOptions {
+	opts := q.PageQuery.Options()
+	opts.TimeRange = q.TimeRange
+
+	return opts
+}
+
+// PageResponse wraps a page of items along with its paging metadata
+type PageResponse struct {
+	Items  interface{} `json:"items"`
+	Total  int         `json:"total"`
+	Limit  int         `json:"limit"`
+	Offset int         `json:"offset"`
+	// Next offset to request the following page with, if there is one
+	Next *int `json:"next"`
+}
+
+// NewPageResponse from the given items and spotify paging metadata
+func NewPageResponse(items interface{}, page internal.SpotifyPage) *PageResponse {
+	resp := &PageResponse{
+		Items:  items,
+		Total:  page.Total,
+		Limit:  page.Limit,
+		Offset: page.Offset,
+	}
+	if page.HasNext() {
+		next := page.Offset + page.Limit
+		resp.Next = &next
+	}
+
+	return resp
+}
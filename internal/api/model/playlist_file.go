@@ -0,0 +1,150 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/flexicon/spotimoods-go/internal"
+)
+
+// Playlist file formats along with their content types
+var playlistContentTypes = map[string]string{
+	"m3u":  "audio/x-mpegurl",
+	"xspf": "application/xspf+xml",
+	"jspf": "application/json",
+}
+
+// PlaylistContentType of the given playlist file format, empty for unsupported formats
+func PlaylistContentType(format string) string {
+	return playlistContentTypes[format]
+}
+
+// RenderPlaylist of the given tracks into a file of the given format
+func RenderPlaylist(format, title string, tracks []*internal.SpotifyTrack) ([]byte, error) {
+	switch format {
+	case "m3u":
+		return renderM3U(title, tracks), nil
+	case "xspf":
+		return renderXSPF(title, tracks)
+	case "jspf":
+		return renderJSPF(title, tracks)
+	}
+
+	return nil, fmt.Errorf("unsupported playlist format: %s", format)
+}
+
+// renderM3U in the extended M3U format, with the spotify URI as the location of every track
+func renderM3U(title string, tracks []*internal.SpotifyTrack) []byte {
+	var b bytes.Buffer
+	b.WriteString("#EXTM3U\n")
+	fmt.Fprintf(&b, "#PLAYLIST:%s\n", m3uEscape(title))
+	for _, track := range tracks {
+		fmt.Fprintf(&b, "#EXTINF:%d,%s - %s\n", track.DurationMs/1000, m3uEscape(artistNames(track)), m3uEscape(track.Name))
+		fmt.Fprintf(&b, "%s\n", track.URI)
+	}
+
+	return b.Bytes()
+}
+
+// xspfPlaylist document
+//
+// Spec: https://xspf.org/spec
+type xspfPlaylist struct {
+	XMLName xml.Name    `xml:"http://xspf.org/ns/0/ playlist"`
+	Version int         `xml:"version,attr"`
+	Title   string      `xml:"title"`
+	Tracks  []xspfTrack `xml:"trackList>track"`
+}
+
+type xspfTrack struct {
+	Location   string `xml:"location"`
+	Identifier string `xml:"identifier,omitempty"`
+	Title      string `xml:"title"`
+	Creator    string `xml:"creator"`
+	Album      string `xml:"album,omitempty"`
+	Duration   int    `xml:"duration"`
+}
+
+func renderXSPF(title string, tracks []*internal.SpotifyTrack) ([]byte, error) {
+	playlist := xspfPlaylist{Version: 1, Title: title, Tracks: make([]xspfTrack, 0, len(tracks))}
+	for _, track := range tracks {
+		playlist.Tracks = append(playlist.Tracks, xspfTrack{
+			Location:   track.URI,
+			Identifier: track.ExternalURLs.Spotify,
+			Title:      track.Name,
+			Creator:    artistNames(track),
+			Album:      albumName(track),
+			Duration:   track.DurationMs,
+		})
+	}
+
+	body, err := xml.MarshalIndent(playlist, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), body...), nil
+}
+
+// jspfPlaylist document, the JSON equivalent of XSPF
+//
+// Spec: https://xspf.org/jspf
+type jspfPlaylist struct {
+	Playlist struct {
+		Title  string      `json:"title"`
+		Tracks []jspfTrack `json:"track"`
+	} `json:"playlist"`
+}
+
+type jspfTrack struct {
+	Location   []string `json:"location"`
+	Identifier []string `json:"identifier,omitempty"`
+	Title      string   `json:"title"`
+	Creator    string   `json:"creator"`
+	Album      string   `json:"album,omitempty"`
+	Duration   int      `json:"duration"`
+}
+
+func renderJSPF(title string, tracks []*internal.SpotifyTrack) ([]byte, error) {
+	var playlist jspfPlaylist
+	playlist.Playlist.Title = title
+	playlist.Playlist.Tracks = make([]jspfTrack, 0, len(tracks))
+	for _, track := range tracks {
+		t := jspfTrack{
+			Location: []string{track.URI},
+			Title:    track.Name,
+			Creator:  artistNames(track),
+			Album:    albumName(track),
+			Duration: track.DurationMs,
+		}
+		if track.ExternalURLs.Spotify != "" {
+			t.Identifier = []string{track.ExternalURLs.Spotify}
+		}
+		playlist.Playlist.Tracks = append(playlist.Playlist.Tracks, t)
+	}
+
+	return json.Marshal(playlist)
+}
+
+func artistNames(track *internal.SpotifyTrack) string {
+	names := make([]string, 0, len(track.Artists))
+	for _, artist := range track.Artists {
+		names = append(names, artist.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+func albumName(track *internal.SpotifyTrack) string {
+	if track.Album == nil {
+		return ""
+	}
+	return track.Album.Name
+}
+
+// m3uEscape keeps values on a single line, since every M3U directive ends with the line
+func m3uEscape(value string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(value)
+}
@@ -0,0 +1,83 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/flexicon/spotimoods-go/internal"
+)
+
+var searchTypes = []string{
+	internal.SearchTypeArtist,
+	internal.SearchTypeTrack,
+	internal.SearchTypeAlbum,
+	internal.SearchTypePlaylist,
+}
+
+// SearchQuery for requesting a page of search results for the given types
+type SearchQuery struct {
+	PageQuery
+	Type string `query:"type" validate:"required"`
+}
+
+// Validate struct fields and every requested search type
+func (q *SearchQuery) Validate() error {
+	if err := validate.Struct(q); err != nil {
+		return err
+	}
+
+	for _, t := range q.Types() {
+		if !isSearchType(t) {
+			return fmt.Errorf("unsupported search type %q, expected one of: %s", t, strings.Join(searchTypes, ", "))
+		}
+	}
+	return nil
+}
+
+// Types requested as a comma separated list
+func (q *SearchQuery) Types() []string {
+	types := make([]string, 0)
+	for _, t := range strings.Split(q.Type, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			types = append(types, t)
+		}
+	}
+
+	return types
+}
+
+func isSearchType(t string) bool {
+	for _, st := range searchTypes {
+		if st == t {
+			return true
+		}
+	}
+	return false
+}
+
+// SearchResponse with a page of results for every searched type
+type SearchResponse struct {
+	Artists   *PageResponse `json:"artists,omitempty"`
+	Tracks    *PageResponse `json:"tracks,omitempty"`
+	Albums    *PageResponse `json:"albums,omitempty"`
+	Playlists *PageResponse `json:"playlists,omitempty"`
+}
+
+// NewSearchResponse from the given spotify search result
+func NewSearchResponse(result *internal.SpotifySearchResult) *SearchResponse {
+	resp := &SearchResponse{}
+	if result.Artists != nil {
+		resp.Artists = NewPageResponse(result.Artists.Items, result.Artists.SpotifyPage)
+	}
+	if result.Tracks != nil {
+		resp.Tracks = NewPageResponse(result.Tracks.Items, result.Tracks.SpotifyPage)
+	}
+	if result.Albums != nil {
+		resp.Albums = NewPageResponse(result.Albums.Items, result.Albums.SpotifyPage)
+	}
+	if result.Playlists != nil {
+		resp.Playlists = NewPageResponse(result.Playlists.Items, result.Playlists.SpotifyPage)
+	}
+
+	return resp
+}
@@ -0,0 +1,21 @@
+package model
+
+// SettingsPayload for the user settings
+type SettingsPayload struct {
+	DriftPolicy string `json:"drift_policy" validate:"required,oneof=push pull flag"`
+}
+
+// Validate struct fields
+func (p *SettingsPayload) Validate() error {
+	return validate.Struct(p)
+}
+
+// ResolveDriftPayload for resolving a flagged playlist drift of a Mood
+type ResolveDriftPayload struct {
+	Policy string `json:"policy" validate:"required,oneof=push pull"`
+}
+
+// Validate struct fields
+func (p *ResolveDriftPayload) Validate() error {
+	return validate.Struct(p)
+}
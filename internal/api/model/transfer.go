@@ -0,0 +1,255 @@
+package model
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/flexicon/spotimoods-go/internal"
+)
+
+// maxImportMoods in a single import
+const maxImportMoods = 200
+
+// csvHeader of mood exports, with a row for every tag of a mood
+var csvHeader = []string{"mood", "color", "type", "id", "name"}
+
+// MoodsExport of all moods of a user, which is accepted back as an import
+type MoodsExport struct {
+	Moods []ExportedMood `json:"moods"`
+}
+
+// ExportedMood in the import and export format
+type ExportedMood struct {
+	Name  string `json:"name" validate:"required,lte=64"`
+	Color string `json:"color" validate:"required,hexcolor"`
+	// Description template, which may use the {name}, {artists} and {date} placeholders
+	Description   string `json:"description,omitempty" validate:"lte=300"`
+	CoverInitials bool   `json:"cover_initials,omitempty"`
+	// Public defaults to true, unless the playlist is collaborative
+	Public        *bool                  `json:"public,omitempty"`
+	Collaborative bool                   `json:"collaborative,omitempty"`
+	Tags          []ExportedTag          `json:"tags" validate:"lte=100"`
+	Features      *internal.MoodFeatures `json:"features,omitempty"`
+}
+
+// ExportedTag of a mood, where artists may be given by name alone
+type ExportedTag struct {
+	Type string `json:"type" validate:"required,oneof=artist track album genre"`
+	ID   string `json:"id,omitempty" validate:"lte=128"`
+	Name string `json:"name,omitempty" validate:"lte=256"`
+}
+
+// ImportResponse with either the created moods or the errors of every failed row
+type ImportResponse struct {
+	Moods  []*internal.Mood       `json:"moods"`
+	Errors []internal.ImportError `json:"errors,omitempty"`
+}
+
+// Validate struct fields
+func (t *ExportedTag) Validate() error {
+	t.ID = strings.TrimSpace(t.ID)
+	t.Name = strings.TrimSpace(t.Name)
+	if err := validate.Struct(t); err != nil {
+		return err
+	}
+
+	if t.ID == "" && (t.Type != internal.TagTypeArtist || t.Name == "") {
+		return errors.New("tag requires an id, only artists may be given by name")
+	}
+	return nil
+}
+
+// NewMoodsExport of the given moods, naming tagged artists found in the given lookup
+func NewMoodsExport(moods []*internal.Mood, artists map[string]*internal.SpotifyArtist) *MoodsExport {
+	export := &MoodsExport{Moods: make([]ExportedMood, 0, len(moods))}
+	for _, mood := range moods {
+		public := mood.Public
+		exported := ExportedMood{
+			Name:          mood.Name,
+			Color:         mood.Color,
+			Description:   mood.Description,
+			CoverInitials: mood.CoverInitials,
+			Public:        &public,
+			Collaborative: mood.Collaborative,
+			Tags:          make([]ExportedTag, 0, len(mood.Tags)),
+		}
+		if mood.Features.IsSet() {
+			features := mood.Features
+			exported.Features = &features
+		}
+
+		for _, tag := range mood.Tags {
+			t := ExportedTag{Type: tag.Type, ID: tag.SpotifyID}
+			if artist, ok := artists[tag.SpotifyID]; ok && tag.Type == internal.TagTypeArtist {
+				t.Name = artist.Name
+			}
+			exported.Tags = append(exported.Tags, t)
+		}
+
+		export.Moods = append(export.Moods, exported)
+	}
+
+	return export
+}
+
+// WriteCSV of the export, with a row for every tag and a single row for moods without tags.
+// Feature ranges are left out, as only the JSON export is fully round-trippable.
+func (e *MoodsExport) WriteCSV(out io.Writer) error {
+	w := csv.NewWriter(out)
+	if err := w.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for _, mood := range e.Moods {
+		if len(mood.Tags) == 0 {
+			if err := w.Write([]string{mood.Name, mood.Color, "", "", ""}); err != nil {
+				return err
+			}
+		}
+		for _, tag := range mood.Tags {
+			if err := w.Write([]string{mood.Name, mood.Color, tag.Type, tag.ID, tag.Name}); err != nil {
+				return err
+			}
+		}
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+// Imports validates every mood of the export, numbering rows by their position in the list
+func (e *MoodsExport) Imports() ([]internal.MoodImport, []internal.ImportError) {
+	if len(e.Moods) > maxImportMoods {
+		return nil, []internal.ImportError{{Msg: fmt.Sprintf("at most %d moods can be imported at once", maxImportMoods)}}
+	}
+
+	imports := make([]internal.MoodImport, 0, len(e.Moods))
+	var importErrs []internal.ImportError
+	for i, mood := range e.Moods {
+		imp, err := mood.toImport(i + 1)
+		if err != nil {
+			importErrs = append(importErrs, internal.ImportError{Row: i + 1, Msg: err.Error()})
+			continue
+		}
+		imports = append(imports, imp)
+	}
+
+	return imports, importErrs
+}
+
+// ReadCSVImport of moods in the CSV export format, numbering rows by their line.
+// Rows of the same mood name and color are gathered into a single mood, reported at its first line.
+func ReadCSVImport(in io.Reader) ([]internal.MoodImport, []internal.ImportError, error) {
+	r := csv.NewReader(in)
+	r.FieldsPerRecord = len(csvHeader)
+	r.TrimLeadingSpace = true
+
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(records) == 0 || strings.Join(records[0], ",") != strings.Join(csvHeader, ",") {
+		return nil, nil, fmt.Errorf("csv header must be: %s", strings.Join(csvHeader, ","))
+	}
+
+	type csvMood struct {
+		line int
+		mood ExportedMood
+	}
+	var moods []*csvMood
+	byKey := make(map[string]*csvMood)
+	var importErrs []internal.ImportError
+
+	for i, record := range records[1:] {
+		line := i + 2
+		key := record[0] + "\x00" + record[1]
+		m, ok := byKey[key]
+		if !ok {
+			m = &csvMood{line: line, mood: ExportedMood{Name: record[0], Color: record[1]}}
+			byKey[key] = m
+			moods = append(moods, m)
+		}
+
+		// Moods without tags are exported as a single row with empty tag columns
+		if record[2] == "" && record[3] == "" && record[4] == "" {
+			continue
+		}
+
+		tag := ExportedTag{Type: record[2], ID: record[3], Name: record[4]}
+		if err := tag.Validate(); err != nil {
+			importErrs = append(importErrs, internal.ImportError{Row: line, Msg: err.Error()})
+			continue
+		}
+		m.mood.Tags = append(m.mood.Tags, tag)
+	}
+
+	if len(moods) > maxImportMoods {
+		return nil, nil, fmt.Errorf("at most %d moods can be imported at once", maxImportMoods)
+	}
+
+	imports := make([]internal.MoodImport, 0, len(moods))
+	for _, m := range moods {
+		imp, err := m.mood.toImport(m.line)
+		if err != nil {
+			importErrs = append(importErrs, internal.ImportError{Row: m.line, Msg: err.Error()})
+			continue
+		}
+		imports = append(imports, imp)
+	}
+	sort.SliceStable(importErrs, func(i, j int) bool {
+		return importErrs[i].Row < importErrs[j].Row
+	})
+
+	return imports, importErrs, nil
+}
+
+// toImport validates the mood and converts it into an import at the given row
+func (m ExportedMood) toImport(row int) (internal.MoodImport, error) {
+	m.Name = strings.TrimSpace(m.Name)
+	if err := validate.Struct(&m); err != nil {
+		return internal.MoodImport{}, err
+	}
+	if m.Collaborative && m.Public != nil && *m.Public {
+		return internal.MoodImport{}, internal.ErrPublicCollaborative
+	}
+	if err := validateFeatures(m.Features); err != nil {
+		return internal.MoodImport{}, err
+	}
+
+	payloads := make([]TagPayload, 0, len(m.Tags))
+	var names []string
+	for i := range m.Tags {
+		tag := &m.Tags[i]
+		if err := tag.Validate(); err != nil {
+			return internal.MoodImport{}, err
+		}
+
+		if tag.ID == "" {
+			names = append(names, tag.Name)
+			continue
+		}
+		payloads = append(payloads, TagPayload{Type: tag.Type, ID: tag.ID})
+	}
+
+	mood := &internal.Mood{
+		Name:          m.Name,
+		Color:         m.Color,
+		Description:   m.Description,
+		CoverInitials: m.CoverInitials,
+		Public:        !m.Collaborative,
+		Collaborative: m.Collaborative,
+		Tags:          toTags(payloads),
+	}
+	if m.Public != nil {
+		mood.Public = *m.Public
+	}
+	if m.Features != nil {
+		mood.Features = *m.Features
+	}
+
+	return internal.MoodImport{Row: row, Mood: mood, ArtistNames: names}, nil
+}
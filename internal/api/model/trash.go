@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+
+	"github.com/flexicon/spotimoods-go/internal"
+)
+
+// TrashedMood which can still be restored until the end of its undo window
+type TrashedMood struct {
+	Mood            *internal.Mood `json:"mood"`
+	DeletedAt       time.Time      `json:"deleted_at"`
+	RestorableUntil time.Time      `json:"restorable_until"`
+}
+
+// NewTrash of the given deleted moods, with the given undo window
+func NewTrash(moods []*internal.Mood, undoWindow time.Duration) []TrashedMood {
+	trash := make([]TrashedMood, 0, len(moods))
+	for _, mood := range moods {
+		trash = append(trash, TrashedMood{
+			Mood:            mood,
+			DeletedAt:       *mood.DeletedAt,
+			RestorableUntil: mood.DeletedAt.Add(undoWindow),
+		})
+	}
+
+	return trash
+}
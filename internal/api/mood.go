@@ -1,14 +1,17 @@
 package api
 
 import (
+	"fmt"
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/flexicon/spotimoods-go/internal"
 	"github.com/flexicon/spotimoods-go/internal/api/model"
 	"github.com/labstack/echo/v4"
 	"github.com/pkg/errors"
+	"github.com/spf13/viper"
 )
 
 type moodController struct {
@@ -27,21 +30,62 @@ func (h *moodController) Routes(g *echo.Group) {
 
 	g.GET("", h.List())
 	g.POST("", h.Create())
+	g.POST("/suggestions", h.Suggestions())
+	g.POST("/suggestions/accept", h.AcceptSuggestion())
+	g.GET("/stats", h.AllStats())
+	g.GET("/trash", h.Trash())
+	g.GET("/export", h.Export())
+	g.POST("/import", h.Import())
+	g.POST("/adopt", h.Adopt())
+	g.PUT("/order", h.Order())
 	g.GET("/:id", h.Show())
 	g.PUT("/:id", h.Update())
 	g.DELETE("/:id", h.Delete())
+	g.POST("/:id/restore", h.Restore())
+	g.GET("/:id/plays", h.PlayCounts())
+	g.GET("/:id/stats", h.Stats())
+	g.GET("/:id/history", h.History())
+	g.POST("/:id/share", h.Share())
+	g.DELETE("/:id/share", h.Unshare())
+	g.POST("/:id/clone", h.Clone())
+	g.POST("/:id/drift/resolve", h.ResolveDrift())
+	g.GET("/:id/playlist.:format", h.PlaylistFile())
+	g.GET("/:id/snapshots", h.Snapshots())
+	g.POST("/:id/snapshots/:sid/restore", h.RestoreSnapshot())
+	g.GET("/:id/blocklist", h.Blocklist())
+	g.POST("/:id/blocklist", h.Block())
+	g.DELETE("/:id/blocklist/:type/:spotify_id", h.Unblock())
 }
 
 func (h *moodController) List() echo.HandlerFunc {
 	return func(c echo.Context) error {
+		q := &model.MoodsQuery{}
+		if err := c.Bind(q); err != nil {
+			log.Printf("Failed to bind query params: %v", err)
+			return c.NoContent(http.StatusBadRequest)
+		}
+		if err := q.Validate(); err != nil {
+			return c.JSON(http.StatusBadRequest, ErrResponse{Msg: err.Error()})
+		}
+		query, err := q.Query()
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, ErrResponse{Msg: err.Error()})
+		}
+
 		user := c.Get("user").(*internal.User)
-		moods, err := h.services.Mood().GetMoods(user)
+		moods, next, err := h.services.Mood().GetMoods(user, query)
 		if err != nil {
 			log.Printf("Failed to get moods for user (ID: %d): %v", user.ID, err)
 			return c.JSON(http.StatusInternalServerError, ErrResponse{Msg: "Failed to get moods"})
 		}
 
-		return c.JSON(http.StatusOK, moods)
+		resp, err := model.NewMoodsResponse(moods, next)
+		if err != nil {
+			log.Printf("Failed to encode moods cursor for user (ID: %d): %v", user.ID, err)
+			return c.JSON(http.StatusInternalServerError, ErrResponse{Msg: "Failed to get moods"})
+		}
+
+		return c.JSON(http.StatusOK, resp)
 	}
 }
 
@@ -60,7 +104,7 @@ func (h *moodController) Create() echo.HandlerFunc {
 		}
 
 		user := c.Get("user").(*internal.User)
-		mood, err := h.services.Mood().AddMood(payload.Name, payload.Color, user)
+		mood, err := h.services.Mood().AddMood(payload.Mood(), user)
 		if err != nil {
 			log.Printf("Failed to add mood: %v", err)
 			return c.JSON(http.StatusInternalServerError, ErrResponse{Msg: "Failed to add mood"})
@@ -70,6 +114,166 @@ func (h *moodController) Create() echo.HandlerFunc {
 	}
 }
 
+func (h *moodController) Suggestions() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		token := c.Get("user.spotify_token").(*internal.SpotifyToken)
+
+		suggestions, err := h.services.Mood().SuggestMoods(token)
+		if err != nil {
+			log.Printf("Failed to suggest moods for user (ID: %d): %v", token.UserID, err)
+			return c.JSON(http.StatusInternalServerError, ErrResponse{Msg: "Failed to suggest moods"})
+		}
+
+		return c.JSON(http.StatusOK, suggestions)
+	}
+}
+
+func (h *moodController) AcceptSuggestion() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		payload := &model.MoodPayload{}
+		if err := c.Bind(payload); err != nil {
+			log.Printf("Failed to bind request body: %v", err)
+			return c.NoContent(http.StatusBadRequest)
+		}
+
+		if err := payload.Validate(); err != nil {
+			log.Printf("Payload did not pass validation: %+v", payload)
+			log.Printf("Validation error: %v", err)
+			return c.JSON(http.StatusBadRequest, ErrResponse{Msg: err.Error()})
+		}
+
+		user := c.Get("user").(*internal.User)
+		mood, err := h.services.Mood().AddMood(payload.Mood(), user)
+		if err != nil {
+			log.Printf("Failed to add suggested mood: %v", err)
+			return c.JSON(http.StatusInternalServerError, ErrResponse{Msg: "Failed to add mood"})
+		}
+
+		return c.JSON(http.StatusOK, mood)
+	}
+}
+
+func (h *moodController) Export() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		token := c.Get("user.spotify_token").(*internal.SpotifyToken)
+
+		moods, artists, err := h.services.Mood().ExportForUser(token)
+		if err != nil {
+			log.Printf("Failed to export moods for user (ID: %d): %v", token.UserID, err)
+			return c.JSON(http.StatusInternalServerError, ErrResponse{Msg: "failed to export moods"})
+		}
+		export := model.NewMoodsExport(moods, artists)
+
+		switch c.QueryParam("format") {
+		case "", "json":
+			return c.JSON(http.StatusOK, export)
+		case "csv":
+			c.Response().Header().Set(echo.HeaderContentType, "text/csv; charset=utf-8")
+			c.Response().Header().Set(echo.HeaderContentDisposition, `attachment; filename="moods.csv"`)
+			c.Response().WriteHeader(http.StatusOK)
+			return export.WriteCSV(c.Response())
+		default:
+			return c.JSON(http.StatusBadRequest, ErrResponse{Msg: "format must be one of: json csv"})
+		}
+	}
+}
+
+func (h *moodController) Import() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		token := c.Get("user.spotify_token").(*internal.SpotifyToken)
+
+		var imports []internal.MoodImport
+		var importErrs []internal.ImportError
+		if strings.HasPrefix(c.Request().Header.Get(echo.HeaderContentType), "text/csv") {
+			var err error
+			imports, importErrs, err = model.ReadCSVImport(c.Request().Body)
+			if err != nil {
+				return c.JSON(http.StatusBadRequest, ErrResponse{Msg: err.Error()})
+			}
+		} else {
+			payload := &model.MoodsExport{}
+			if err := c.Bind(payload); err != nil {
+				log.Printf("Failed to bind request body: %v", err)
+				return c.NoContent(http.StatusBadRequest)
+			}
+			imports, importErrs = payload.Imports()
+		}
+		if len(importErrs) > 0 {
+			return c.JSON(http.StatusUnprocessableEntity, model.ImportResponse{Moods: []*internal.Mood{}, Errors: importErrs})
+		}
+
+		moods, importErrs, err := h.services.Mood().ImportForUser(imports, token)
+		if err != nil {
+			log.Printf("Failed to import moods for user (ID: %d): %v", token.UserID, err)
+			return c.JSON(http.StatusInternalServerError, ErrResponse{Msg: "failed to import moods"})
+		}
+		if len(importErrs) > 0 {
+			return c.JSON(http.StatusUnprocessableEntity, model.ImportResponse{Moods: []*internal.Mood{}, Errors: importErrs})
+		}
+
+		return c.JSON(http.StatusOK, model.ImportResponse{Moods: moods})
+	}
+}
+
+func (h *moodController) Adopt() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		token := c.Get("user.spotify_token").(*internal.SpotifyToken)
+
+		payload := &model.AdoptPayload{}
+		if err := c.Bind(payload); err != nil {
+			log.Printf("Failed to bind request body: %v", err)
+			return c.NoContent(http.StatusBadRequest)
+		}
+
+		if err := payload.Validate(); err != nil {
+			log.Printf("Payload did not pass validation: %+v", payload)
+			log.Printf("Validation error: %v", err)
+			return c.JSON(http.StatusBadRequest, ErrResponse{Msg: err.Error()})
+		}
+
+		mood, err := h.services.Mood().AdoptPlaylist(payload.PlaylistID, payload.Mood(), token)
+		if err != nil {
+			switch err {
+			case internal.ErrNotOwner:
+				return c.JSON(http.StatusForbidden, ErrResponse{Msg: err.Error()})
+			case internal.ErrAdopted:
+				return c.JSON(http.StatusConflict, ErrResponse{Msg: err.Error()})
+			}
+			log.Printf("Failed to adopt playlist (ID: %s): %v", payload.PlaylistID, err)
+			return c.JSON(http.StatusInternalServerError, ErrResponse{Msg: "failed to adopt playlist"})
+		}
+
+		return c.JSON(http.StatusOK, mood)
+	}
+}
+
+func (h *moodController) Order() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		payload := &model.OrderPayload{}
+		if err := c.Bind(payload); err != nil {
+			log.Printf("Failed to bind request body: %v", err)
+			return c.NoContent(http.StatusBadRequest)
+		}
+
+		if err := payload.Validate(); err != nil {
+			log.Printf("Payload did not pass validation: %+v", payload)
+			log.Printf("Validation error: %v", err)
+			return c.JSON(http.StatusBadRequest, ErrResponse{Msg: err.Error()})
+		}
+
+		user := c.Get("user").(*internal.User)
+		if err := h.services.Mood().ReorderForUser(user, payload.IDs); err != nil {
+			if err == internal.ErrInvalidOrder {
+				return c.JSON(http.StatusBadRequest, ErrResponse{Msg: err.Error()})
+			}
+			log.Printf("Failed to reorder moods for user (ID: %d): %v", user.ID, err)
+			return c.JSON(http.StatusInternalServerError, ErrResponse{Msg: "failed to reorder moods"})
+		}
+
+		return c.NoContent(http.StatusOK)
+	}
+}
+
 func (h *moodController) Show() echo.HandlerFunc {
 	return func(c echo.Context) error {
 		token := c.Get("user.spotify_token").(*internal.SpotifyToken)
@@ -84,22 +288,12 @@ func (h *moodController) Show() echo.HandlerFunc {
 			return notFound(c, "mood")
 		}
 
-		// Populate artist data in mood tags
-		artistIDs := make([]string, 0)
-		for _, tag := range mood.Tags {
-			artistIDs = append(artistIDs, tag.ArtistID)
-		}
-
-		artists, err := h.services.Spotify().GetArtistsByIDs(token, artistIDs)
-		if err != nil {
-			err := errors.Wrap(err, "failed to retrieve mood artist data")
+		// Populate spotify data in mood tags
+		if err := h.services.Mood().PopulateTags(mood, token); err != nil {
+			err := errors.Wrap(err, "failed to retrieve mood tag data")
 			return c.JSON(http.StatusInternalServerError, ErrResponse{Msg: err.Error()})
 		}
 
-		for i, artist := range artists {
-			mood.Tags[i].ArtistData = *artist
-		}
-
 		return c.JSON(http.StatusOK, mood)
 	}
 }
@@ -124,16 +318,14 @@ func (h *moodController) Update() echo.HandlerFunc {
 			return c.JSON(http.StatusBadRequest, ErrResponse{Msg: err.Error()})
 		}
 
-		changes := internal.Mood{
-			Name:  payload.Name,
-			Color: payload.Color,
-		}
-
-		mood, err := h.services.Mood().UpdateMoodForUser(uint(id), changes, user)
+		mood, err := h.services.Mood().UpdateMoodForUser(uint(id), payload.Changes(), user)
 		if err != nil {
 			if err == internal.ErrNotFound {
 				return notFound(c, "mood")
 			}
+			if err == internal.ErrPublicCollaborative {
+				return c.JSON(http.StatusBadRequest, ErrResponse{Msg: err.Error()})
+			}
 			log.Printf("Failed to update mood: %v", err)
 			log.Printf("Payload: %+v", payload)
 			return c.JSON(http.StatusInternalServerError, ErrResponse{Msg: "failed to update mood"})
@@ -164,3 +356,410 @@ func (h *moodController) Delete() echo.HandlerFunc {
 		return c.NoContent(http.StatusOK)
 	}
 }
+
+func (h *moodController) Trash() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		user := c.Get("user").(*internal.User)
+		undoWindow := viper.GetDuration("moods.undo_window")
+
+		moods, err := h.services.Mood().GetTrashForUser(user, undoWindow)
+		if err != nil {
+			log.Printf("Failed to get trashed moods for user (ID: %d): %v", user.ID, err)
+			return c.JSON(http.StatusInternalServerError, ErrResponse{Msg: "failed to get trashed moods"})
+		}
+
+		return c.JSON(http.StatusOK, model.NewTrash(moods, undoWindow))
+	}
+}
+
+func (h *moodController) Restore() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		user := c.Get("user").(*internal.User)
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return notFound(c, "mood")
+		}
+
+		mood, err := h.services.Mood().RestoreForUser(uint(id), user, viper.GetDuration("moods.undo_window"))
+		if err != nil {
+			if err == internal.ErrNotFound {
+				return notFound(c, "deleted mood")
+			}
+			log.Printf("Failed to restore mood (ID: %d): %v", id, err)
+			return c.JSON(http.StatusInternalServerError, ErrResponse{Msg: "failed to restore mood"})
+		}
+
+		return c.JSON(http.StatusOK, mood)
+	}
+}
+
+func (h *moodController) PlayCounts() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		user := c.Get("user").(*internal.User)
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return notFound(c, "mood")
+		}
+
+		query := &model.PlayCountsQuery{}
+		if err := c.Bind(query); err != nil {
+			log.Printf("Failed to bind query params: %v", err)
+			return c.NoContent(http.StatusBadRequest)
+		}
+		if err := query.Validate(); err != nil {
+			return c.JSON(http.StatusBadRequest, ErrResponse{Msg: err.Error()})
+		}
+
+		mood, err := h.services.Mood().FindForUser(uint(id), user)
+		if err != nil {
+			return notFound(c, "mood")
+		}
+
+		counts, err := h.services.History().GetPlayCounts(mood, query.Days)
+		if err != nil {
+			log.Printf("Failed to get play counts for mood (ID: %d): %v", mood.ID, err)
+			return c.JSON(http.StatusInternalServerError, ErrResponse{Msg: "failed to get play counts"})
+		}
+
+		return c.JSON(http.StatusOK, counts)
+	}
+}
+
+func (h *moodController) AllStats() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		token := c.Get("user.spotify_token").(*internal.SpotifyToken)
+
+		stats, err := h.services.Stats().GetForUser(token)
+		if err != nil {
+			log.Printf("Failed to get mood stats for user (ID: %d): %v", token.UserID, err)
+			return c.JSON(http.StatusInternalServerError, ErrResponse{Msg: "failed to get mood stats"})
+		}
+
+		return c.JSON(http.StatusOK, stats)
+	}
+}
+
+func (h *moodController) Stats() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		token := c.Get("user.spotify_token").(*internal.SpotifyToken)
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return notFound(c, "mood")
+		}
+
+		mood, err := h.services.Mood().FindForUser(uint(id), &token.User)
+		if err != nil {
+			return notFound(c, "mood")
+		}
+
+		stats, err := h.services.Stats().GetForMood(mood, token)
+		if err != nil {
+			log.Printf("Failed to get stats for mood (ID: %d): %v", mood.ID, err)
+			return c.JSON(http.StatusInternalServerError, ErrResponse{Msg: "failed to get mood stats"})
+		}
+
+		return c.JSON(http.StatusOK, stats)
+	}
+}
+
+func (h *moodController) Share() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		user := c.Get("user").(*internal.User)
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return notFound(c, "mood")
+		}
+
+		mood, err := h.services.Mood().ShareForUser(uint(id), user)
+		if err != nil {
+			if err == internal.ErrNotFound {
+				return notFound(c, "mood")
+			}
+			log.Printf("Failed to share mood (ID: %d): %v", id, err)
+			return c.JSON(http.StatusInternalServerError, ErrResponse{Msg: "failed to share mood"})
+		}
+
+		return c.JSON(http.StatusOK, mood)
+	}
+}
+
+func (h *moodController) Unshare() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		user := c.Get("user").(*internal.User)
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return notFound(c, "mood")
+		}
+
+		mood, err := h.services.Mood().UnshareForUser(uint(id), user)
+		if err != nil {
+			if err == internal.ErrNotFound {
+				return notFound(c, "mood")
+			}
+			log.Printf("Failed to unshare mood (ID: %d): %v", id, err)
+			return c.JSON(http.StatusInternalServerError, ErrResponse{Msg: "failed to unshare mood"})
+		}
+
+		return c.JSON(http.StatusOK, mood)
+	}
+}
+
+func (h *moodController) Clone() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		user := c.Get("user").(*internal.User)
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return notFound(c, "mood")
+		}
+
+		source, err := h.services.Mood().FindForUser(uint(id), user)
+		if err != nil {
+			return notFound(c, "mood")
+		}
+
+		mood, err := h.services.Mood().CloneForUser(source, user)
+		if err != nil {
+			log.Printf("Failed to clone mood (ID: %d): %v", source.ID, err)
+			return c.JSON(http.StatusInternalServerError, ErrResponse{Msg: "failed to clone mood"})
+		}
+
+		return c.JSON(http.StatusOK, mood)
+	}
+}
+
+func (h *moodController) ResolveDrift() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		token := c.Get("user.spotify_token").(*internal.SpotifyToken)
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return notFound(c, "mood")
+		}
+
+		payload := &model.ResolveDriftPayload{}
+		if err := c.Bind(payload); err != nil {
+			log.Printf("Failed to bind request body: %v", err)
+			return c.NoContent(http.StatusBadRequest)
+		}
+
+		if err := payload.Validate(); err != nil {
+			log.Printf("Payload did not pass validation: %+v", payload)
+			log.Printf("Validation error: %v", err)
+			return c.JSON(http.StatusBadRequest, ErrResponse{Msg: err.Error()})
+		}
+
+		mood, err := h.services.Mood().FindForUser(uint(id), &token.User)
+		if err != nil {
+			return notFound(c, "mood")
+		}
+
+		if err := h.services.Mood().ResolveDrift(mood, payload.Policy, token); err != nil {
+			log.Printf("Failed to resolve playlist drift for mood (ID: %d): %v", mood.ID, err)
+			return c.JSON(http.StatusInternalServerError, ErrResponse{Msg: "failed to resolve playlist drift"})
+		}
+
+		return c.JSON(http.StatusOK, mood)
+	}
+}
+
+func (h *moodController) PlaylistFile() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		token := c.Get("user.spotify_token").(*internal.SpotifyToken)
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return notFound(c, "mood")
+		}
+
+		format := c.Param("format")
+		contentType := model.PlaylistContentType(format)
+		if contentType == "" {
+			return notFound(c, "playlist format")
+		}
+
+		mood, err := h.services.Mood().FindForUser(uint(id), &token.User)
+		if err != nil {
+			return notFound(c, "mood")
+		}
+
+		tracks, err := h.services.Mood().GetPlaylistTracks(mood, token)
+		if err != nil {
+			if err == internal.ErrNoPlaylist {
+				return notFound(c, "playlist")
+			}
+			log.Printf("Failed to get playlist tracks for mood (ID: %d): %v", mood.ID, err)
+			return c.JSON(http.StatusInternalServerError, ErrResponse{Msg: "failed to get playlist tracks"})
+		}
+
+		body, err := model.RenderPlaylist(format, mood.Name, tracks)
+		if err != nil {
+			log.Printf("Failed to render %s playlist for mood (ID: %d): %v", format, mood.ID, err)
+			return c.JSON(http.StatusInternalServerError, ErrResponse{Msg: "failed to render playlist"})
+		}
+
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="mood-%d.%s"`, mood.ID, format))
+		return c.Blob(http.StatusOK, contentType, body)
+	}
+}
+
+func (h *moodController) History() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		user := c.Get("user").(*internal.User)
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return notFound(c, "mood")
+		}
+
+		// The history stays readable for moods in the trash, as it tells how they got there
+		mood, err := h.services.Mood().FindWithTrashForUser(uint(id), user)
+		if err != nil {
+			return notFound(c, "mood")
+		}
+
+		entries, err := h.services.Mood().GetHistory(mood)
+		if err != nil {
+			log.Printf("Failed to get history for mood (ID: %d): %v", mood.ID, err)
+			return c.JSON(http.StatusInternalServerError, ErrResponse{Msg: "failed to get mood history"})
+		}
+
+		return c.JSON(http.StatusOK, entries)
+	}
+}
+
+func (h *moodController) Snapshots() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		user := c.Get("user").(*internal.User)
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return notFound(c, "mood")
+		}
+
+		mood, err := h.services.Mood().FindForUser(uint(id), user)
+		if err != nil {
+			return notFound(c, "mood")
+		}
+
+		snapshots, err := h.services.Mood().GetSnapshots(mood)
+		if err != nil {
+			log.Printf("Failed to get snapshots for mood (ID: %d): %v", mood.ID, err)
+			return c.JSON(http.StatusInternalServerError, ErrResponse{Msg: "failed to get snapshots"})
+		}
+
+		return c.JSON(http.StatusOK, snapshots)
+	}
+}
+
+func (h *moodController) RestoreSnapshot() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		user := c.Get("user").(*internal.User)
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return notFound(c, "mood")
+		}
+		snapshotID, err := strconv.Atoi(c.Param("sid"))
+		if err != nil {
+			return notFound(c, "snapshot")
+		}
+
+		mood, err := h.services.Mood().FindForUser(uint(id), user)
+		if err != nil {
+			return notFound(c, "mood")
+		}
+
+		snapshot, err := h.services.Mood().QueueRestoreForUser(uint(snapshotID), mood, user)
+		if err != nil {
+			switch err {
+			case internal.ErrNotFound:
+				return notFound(c, "snapshot")
+			case internal.ErrNoPlaylist:
+				return notFound(c, "playlist")
+			}
+			log.Printf("Failed to queue snapshot restore for mood (ID: %d): %v", mood.ID, err)
+			return c.JSON(http.StatusInternalServerError, ErrResponse{Msg: "failed to restore snapshot"})
+		}
+
+		return c.JSON(http.StatusAccepted, snapshot)
+	}
+}
+
+func (h *moodController) Blocklist() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		user := c.Get("user").(*internal.User)
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return notFound(c, "mood")
+		}
+
+		mood, err := h.services.Mood().FindForUser(uint(id), user)
+		if err != nil {
+			return notFound(c, "mood")
+		}
+
+		blocks, err := h.services.Block().GetForMood(mood)
+		if err != nil {
+			log.Printf("Failed to get blocklist for mood (ID: %d): %v", mood.ID, err)
+			return c.JSON(http.StatusInternalServerError, ErrResponse{Msg: "failed to get blocklist"})
+		}
+
+		return c.JSON(http.StatusOK, blocks)
+	}
+}
+
+func (h *moodController) Block() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		user := c.Get("user").(*internal.User)
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return notFound(c, "mood")
+		}
+
+		payload := &model.BlockPayload{}
+		if err := c.Bind(payload); err != nil {
+			log.Printf("Failed to bind request body: %v", err)
+			return c.NoContent(http.StatusBadRequest)
+		}
+
+		if err := payload.Validate(); err != nil {
+			log.Printf("Payload did not pass validation: %+v", payload)
+			log.Printf("Validation error: %v", err)
+			return c.JSON(http.StatusBadRequest, ErrResponse{Msg: err.Error()})
+		}
+
+		mood, err := h.services.Mood().FindForUser(uint(id), user)
+		if err != nil {
+			return notFound(c, "mood")
+		}
+
+		block, err := h.services.Block().BlockForMood(mood, payload.Type, payload.SpotifyID)
+		if err != nil {
+			log.Printf("Failed to block %s for mood (ID: %d): %v", payload.Type, mood.ID, err)
+			return c.JSON(http.StatusInternalServerError, ErrResponse{Msg: "failed to update blocklist"})
+		}
+
+		return c.JSON(http.StatusOK, block)
+	}
+}
+
+func (h *moodController) Unblock() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		user := c.Get("user").(*internal.User)
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			return notFound(c, "mood")
+		}
+
+		mood, err := h.services.Mood().FindForUser(uint(id), user)
+		if err != nil {
+			return notFound(c, "mood")
+		}
+
+		err = h.services.Block().UnblockForMood(mood, c.Param("type"), c.Param("spotify_id"))
+		if err != nil {
+			if err == internal.ErrNotFound {
+				return notFound(c, "block")
+			}
+			log.Printf("Failed to unblock %s for mood (ID: %d): %v", c.Param("type"), mood.ID, err)
+			return c.JSON(http.StatusInternalServerError, ErrResponse{Msg: "failed to update blocklist"})
+		}
+
+		return c.NoContent(http.StatusOK)
+	}
+}
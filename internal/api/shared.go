@@ -0,0 +1,64 @@
+package api
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/flexicon/spotimoods-go/internal"
+	"github.com/labstack/echo/v4"
+)
+
+type sharedController struct {
+	services *internal.ServiceProvider
+}
+
+func newShared(services *internal.ServiceProvider) Controller {
+	return &sharedController{
+		services: services,
+	}
+}
+
+func (h *sharedController) Routes(g *echo.Group) {
+	g = g.Group("/shared")
+
+	g.GET("/moods/:token", h.Show())
+	g.POST("/moods/:token/clone", h.Clone(), authMiddleware(Options{Services: h.services})...)
+}
+
+func (h *sharedController) Show() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		shared, err := h.services.Mood().GetShared(c.Param("token"))
+		if err != nil {
+			if err == internal.ErrNotFound {
+				return notFound(c, "mood")
+			}
+			log.Printf("Failed to get shared mood: %v", err)
+			return c.JSON(http.StatusInternalServerError, ErrResponse{Msg: "failed to get shared mood"})
+		}
+
+		return c.JSON(http.StatusOK, shared)
+	}
+}
+
+func (h *sharedController) Clone() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		user := c.Get("user").(*internal.User)
+
+		source, err := h.services.Mood().FindShared(c.Param("token"))
+		if err != nil {
+			if err == internal.ErrNotFound {
+				return notFound(c, "mood")
+			}
+			log.Printf("Failed to find shared mood: %v", err)
+			return c.JSON(http.StatusInternalServerError, ErrResponse{Msg: "failed to clone mood"})
+		}
+
+		mood, err := h.services.Mood().CloneForUser(source, user)
+		if err != nil {
+			log.Printf("Failed to clone shared mood (ID: %d): %v", source.ID, err)
+			return c.JSON(http.StatusInternalServerError, ErrResponse{Msg: "failed to clone mood"})
+		}
+
+		return c.JSON(http.StatusOK, mood)
+	}
+}
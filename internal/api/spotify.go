@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"github.com/flexicon/spotimoods-go/internal"
+	"github.com/flexicon/spotimoods-go/internal/api/model"
 	"github.com/labstack/echo/v4"
 )
 
@@ -25,6 +26,27 @@ func (h *spotifyController) Routes(g *echo.Group) {
 
 	artists.GET("/search", h.ArtistsSearch())
 	artists.GET("/top", h.TopArtists())
+
+	tracks := g.Group("/tracks")
+	useAuthMiddleware(tracks, Options{Services: h.services})
+
+	tracks.GET("/search", h.TracksSearch())
+	tracks.GET("/top", h.TopTracks())
+
+	albums := g.Group("/albums")
+	useAuthMiddleware(albums, Options{Services: h.services})
+
+	albums.GET("/search", h.AlbumsSearch())
+
+	search := g.Group("/search")
+	useAuthMiddleware(search, Options{Services: h.services})
+
+	search.GET("", h.Search())
+
+	genres := g.Group("/genres")
+	useAuthMiddleware(genres, Options{Services: h.services})
+
+	genres.GET("", h.Genres())
 }
 
 func (h *spotifyController) ArtistsSearch() echo.HandlerFunc {
@@ -34,29 +56,168 @@ func (h *spotifyController) ArtistsSearch() echo.HandlerFunc {
 			return c.JSON(http.StatusBadRequest, ErrResponse{Msg: "search query is required"})
 		}
 
+		page := &model.PageQuery{}
+		if err := c.Bind(page); err != nil {
+			log.Printf("Failed to bind query params: %v", err)
+			return c.NoContent(http.StatusBadRequest)
+		}
+		if err := page.Validate(); err != nil {
+			return c.JSON(http.StatusBadRequest, ErrResponse{Msg: err.Error()})
+		}
+
 		token := c.Get("user.spotify_token").(*internal.SpotifyToken)
-		artists, err := h.services.Spotify().SearchForArtists(token, q)
+		artists, err := h.services.Spotify().SearchForArtists(token, q, page.Options())
 		if err != nil {
 			errMsg := fmt.Sprintf("failed to search for artists: %v", err)
 			log.Printf(errMsg)
 			return c.JSON(http.StatusInternalServerError, ErrResponse{Msg: errMsg})
 		}
 
-		return c.JSON(http.StatusOK, artists)
+		return c.JSON(http.StatusOK, model.NewPageResponse(artists.Items, artists.SpotifyPage))
 	}
 }
 
 func (h *spotifyController) TopArtists() echo.HandlerFunc {
 	return func(c echo.Context) error {
-		token := c.Get("user.spotify_token").(*internal.SpotifyToken)
+		top := &model.TopQuery{}
+		if err := c.Bind(top); err != nil {
+			log.Printf("Failed to bind query params: %v", err)
+			return c.NoContent(http.StatusBadRequest)
+		}
+		if err := top.Validate(); err != nil {
+			return c.JSON(http.StatusBadRequest, ErrResponse{Msg: err.Error()})
+		}
 
-		artists, err := h.services.Spotify().GetTopArtists(token)
+		token := c.Get("user.spotify_token").(*internal.SpotifyToken)
+		artists, err := h.services.Spotify().GetTopArtists(token, top.Options())
 		if err != nil {
 			errMsg := fmt.Sprintf("failed to get top artists: %v", err)
 			log.Printf(errMsg)
 			return c.JSON(http.StatusInternalServerError, ErrResponse{Msg: errMsg})
 		}
 
-		return c.JSON(http.StatusOK, artists)
+		return c.JSON(http.StatusOK, model.NewPageResponse(artists.Items, artists.SpotifyPage))
+	}
+}
+
+func (h *spotifyController) TracksSearch() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		q := c.QueryParam("query")
+		if q == "" {
+			return c.JSON(http.StatusBadRequest, ErrResponse{Msg: "search query is required"})
+		}
+
+		page := &model.PageQuery{}
+		if err := c.Bind(page); err != nil {
+			log.Printf("Failed to bind query params: %v", err)
+			return c.NoContent(http.StatusBadRequest)
+		}
+		if err := page.Validate(); err != nil {
+			return c.JSON(http.StatusBadRequest, ErrResponse{Msg: err.Error()})
+		}
+
+		token := c.Get("user.spotify_token").(*internal.SpotifyToken)
+		tracks, err := h.services.Spotify().SearchForTracks(token, q, page.Options())
+		if err != nil {
+			errMsg := fmt.Sprintf("failed to search for tracks: %v", err)
+			log.Printf(errMsg)
+			return c.JSON(http.StatusInternalServerError, ErrResponse{Msg: errMsg})
+		}
+
+		return c.JSON(http.StatusOK, model.NewPageResponse(tracks.Items, tracks.SpotifyPage))
+	}
+}
+
+func (h *spotifyController) TopTracks() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		top := &model.TopQuery{}
+		if err := c.Bind(top); err != nil {
+			log.Printf("Failed to bind query params: %v", err)
+			return c.NoContent(http.StatusBadRequest)
+		}
+		if err := top.Validate(); err != nil {
+			return c.JSON(http.StatusBadRequest, ErrResponse{Msg: err.Error()})
+		}
+
+		token := c.Get("user.spotify_token").(*internal.SpotifyToken)
+		tracks, err := h.services.Spotify().GetTopTracks(token, top.Options())
+		if err != nil {
+			errMsg := fmt.Sprintf("failed to get top tracks: %v", err)
+			log.Printf(errMsg)
+			return c.JSON(http.StatusInternalServerError, ErrResponse{Msg: errMsg})
+		}
+
+		return c.JSON(http.StatusOK, model.NewPageResponse(tracks.Items, tracks.SpotifyPage))
+	}
+}
+
+func (h *spotifyController) AlbumsSearch() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		q := c.QueryParam("query")
+		if q == "" {
+			return c.JSON(http.StatusBadRequest, ErrResponse{Msg: "search query is required"})
+		}
+
+		page := &model.PageQuery{}
+		if err := c.Bind(page); err != nil {
+			log.Printf("Failed to bind query params: %v", err)
+			return c.NoContent(http.StatusBadRequest)
+		}
+		if err := page.Validate(); err != nil {
+			return c.JSON(http.StatusBadRequest, ErrResponse{Msg: err.Error()})
+		}
+
+		token := c.Get("user.spotify_token").(*internal.SpotifyToken)
+		albums, err := h.services.Spotify().SearchForAlbums(token, q, page.Options())
+		if err != nil {
+			errMsg := fmt.Sprintf("failed to search for albums: %v", err)
+			log.Printf(errMsg)
+			return c.JSON(http.StatusInternalServerError, ErrResponse{Msg: errMsg})
+		}
+
+		return c.JSON(http.StatusOK, model.NewPageResponse(albums.Items, albums.SpotifyPage))
+	}
+}
+
+func (h *spotifyController) Search() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		q := c.QueryParam("query")
+		if q == "" {
+			return c.JSON(http.StatusBadRequest, ErrResponse{Msg: "search query is required"})
+		}
+
+		search := &model.SearchQuery{}
+		if err := c.Bind(search); err != nil {
+			log.Printf("Failed to bind query params: %v", err)
+			return c.NoContent(http.StatusBadRequest)
+		}
+		if err := search.Validate(); err != nil {
+			return c.JSON(http.StatusBadRequest, ErrResponse{Msg: err.Error()})
+		}
+
+		token := c.Get("user.spotify_token").(*internal.SpotifyToken)
+		result, err := h.services.Spotify().Search(token, q, search.Types(), search.Options())
+		if err != nil {
+			errMsg := fmt.Sprintf("failed to search: %v", err)
+			log.Printf(errMsg)
+			return c.JSON(http.StatusInternalServerError, ErrResponse{Msg: errMsg})
+		}
+
+		return c.JSON(http.StatusOK, model.NewSearchResponse(result))
+	}
+}
+
+func (h *spotifyController) Genres() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		token := c.Get("user.spotify_token").(*internal.SpotifyToken)
+
+		genres, err := h.services.Mood().GetGenres(token)
+		if err != nil {
+			errMsg := fmt.Sprintf("failed to get genres: %v", err)
+			log.Printf(errMsg)
+			return c.JSON(http.StatusInternalServerError, ErrResponse{Msg: errMsg})
+		}
+
+		return c.JSON(http.StatusOK, genres)
 	}
 }
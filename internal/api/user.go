@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/flexicon/spotimoods-go/internal"
+	"github.com/flexicon/spotimoods-go/internal/api/model"
 	"github.com/labstack/echo/v4"
 )
 
@@ -23,6 +24,13 @@ func (h *userController) Routes(g *echo.Group) {
 	useAuthMiddleware(g, Options{Services: h.services})
 
 	g.GET("/me", h.Me())
+	g.GET("/me/settings", h.Settings())
+	g.PUT("/me/settings", h.UpdateSettings())
+	g.GET("/me/now-playing", h.NowPlaying())
+	g.GET("/me/history", h.History())
+	g.GET("/me/blocklist", h.Blocklist())
+	g.POST("/me/blocklist", h.Block())
+	g.DELETE("/me/blocklist/:type/:spotify_id", h.Unblock())
 }
 
 func (h *userController) Me() echo.HandlerFunc {
@@ -37,3 +45,130 @@ func (h *userController) Me() echo.HandlerFunc {
 		return c.JSON(http.StatusOK, profile)
 	}
 }
+
+func (h *userController) Settings() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		user := c.Get("user").(*internal.User)
+
+		return c.JSON(http.StatusOK, model.SettingsPayload{DriftPolicy: user.DriftPolicy})
+	}
+}
+
+func (h *userController) UpdateSettings() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		user := c.Get("user").(*internal.User)
+
+		payload := &model.SettingsPayload{}
+		if err := c.Bind(payload); err != nil {
+			log.Printf("Failed to bind request body: %v", err)
+			return c.NoContent(http.StatusBadRequest)
+		}
+
+		if err := payload.Validate(); err != nil {
+			log.Printf("Payload did not pass validation: %+v", payload)
+			log.Printf("Validation error: %v", err)
+			return c.JSON(http.StatusBadRequest, ErrResponse{Msg: err.Error()})
+		}
+
+		if err := h.services.User().SetDriftPolicy(user, payload.DriftPolicy); err != nil {
+			log.Printf("Failed to update settings for user (ID: %d): %v", user.ID, err)
+			return c.JSON(http.StatusInternalServerError, ErrResponse{Msg: "failed to update settings"})
+		}
+
+		return c.JSON(http.StatusOK, payload)
+	}
+}
+
+func (h *userController) NowPlaying() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		token := c.Get("user.spotify_token").(*internal.SpotifyToken)
+		nowPlaying, err := h.services.Mood().GetNowPlaying(token)
+		if err != nil {
+			log.Println("Failed to retrieve currently playing track:", err)
+			return c.JSON(http.StatusInternalServerError, ErrResponse{Msg: "Failed to retrieve currently playing track"})
+		}
+		if nowPlaying == nil {
+			return c.NoContent(http.StatusNoContent)
+		}
+
+		return c.JSON(http.StatusOK, nowPlaying)
+	}
+}
+
+func (h *userController) History() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		query := &model.HistoryQuery{}
+		if err := c.Bind(query); err != nil {
+			log.Printf("Failed to bind query params: %v", err)
+			return c.NoContent(http.StatusBadRequest)
+		}
+		if err := query.Validate(); err != nil {
+			return c.JSON(http.StatusBadRequest, ErrResponse{Msg: err.Error()})
+		}
+
+		user := c.Get("user").(*internal.User)
+		plays, err := h.services.History().GetHistory(user, query.Before, query.Limit)
+		if err != nil {
+			log.Printf("Failed to get listening history for user (ID: %d): %v", user.ID, err)
+			return c.JSON(http.StatusInternalServerError, ErrResponse{Msg: "Failed to get listening history"})
+		}
+
+		return c.JSON(http.StatusOK, model.NewHistoryResponse(plays, query.Limit))
+	}
+}
+
+func (h *userController) Blocklist() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		user := c.Get("user").(*internal.User)
+
+		blocks, err := h.services.Block().GetForUser(user)
+		if err != nil {
+			log.Printf("Failed to get blocklist for user (ID: %d): %v", user.ID, err)
+			return c.JSON(http.StatusInternalServerError, ErrResponse{Msg: "Failed to get blocklist"})
+		}
+
+		return c.JSON(http.StatusOK, blocks)
+	}
+}
+
+func (h *userController) Block() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		payload := &model.BlockPayload{}
+		if err := c.Bind(payload); err != nil {
+			log.Printf("Failed to bind request body: %v", err)
+			return c.NoContent(http.StatusBadRequest)
+		}
+
+		if err := payload.Validate(); err != nil {
+			log.Printf("Payload did not pass validation: %+v", payload)
+			log.Printf("Validation error: %v", err)
+			return c.JSON(http.StatusBadRequest, ErrResponse{Msg: err.Error()})
+		}
+
+		user := c.Get("user").(*internal.User)
+		block, err := h.services.Block().BlockForUser(user, payload.Type, payload.SpotifyID)
+		if err != nil {
+			log.Printf("Failed to block %s for user (ID: %d): %v", payload.Type, user.ID, err)
+			return c.JSON(http.StatusInternalServerError, ErrResponse{Msg: "Failed to update blocklist"})
+		}
+
+		return c.JSON(http.StatusOK, block)
+	}
+}
+
+func (h *userController) Unblock() echo.HandlerFunc {
+	return func(c echo.Context) error {
+		user := c.Get("user").(*internal.User)
+
+		err := h.services.Block().UnblockForUser(user, c.Param("type"), c.Param("spotify_id"))
+		if err != nil {
+			if err == internal.ErrNotFound {
+				return notFound(c, "block")
+			}
+			log.Printf("Failed to unblock %s for user (ID: %d): %v", c.Param("type"), user.ID, err)
+			return c.JSON(http.StatusInternalServerError, ErrResponse{Msg: "Failed to update blocklist"})
+		}
+
+		return c.NoContent(http.StatusOK)
+	}
+}
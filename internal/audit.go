@@ -0,0 +1,170 @@
+package internal
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Audit sources, telling which part of the app changed a mood
+const (
+	AuditSourceAPI       = "api"
+	AuditSourceWorker    = "worker"
+	AuditSourceScheduler = "scheduler"
+)
+
+// Audit actions, telling what happened to a mood
+const (
+	AuditActionCreate  = "create"
+	AuditActionUpdate  = "update"
+	AuditActionTags    = "tags"
+	AuditActionDelete  = "delete"
+	AuditActionRestore = "restore"
+	AuditActionSync    = "sync"
+)
+
+// AuditEntry of a single change to a mood, which is never updated once recorded
+type AuditEntry struct {
+	ID        uint      `gorm:"primary_key" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	MoodID    uint      `gorm:"not null;index" json:"mood_id"`
+	// ActorID of the user who made or requested the change, empty for changes the app made on its own
+	ActorID *uint        `json:"actor_id"`
+	Source  string       `gorm:"size:16;not null" json:"source"`
+	Action  string       `gorm:"size:16;not null" json:"action"`
+	Changes AuditChanges `gorm:"type:text" json:"changes"`
+}
+
+// TableName of mood audit entries
+func (AuditEntry) TableName() string {
+	return "mood_audit_entries"
+}
+
+// AuditChanges of the name, color and tags of a mood, leaving out what stayed the same
+type AuditChanges struct {
+	Name  *FieldChange `json:"name,omitempty"`
+	Color *FieldChange `json:"color,omitempty"`
+	Tags  *TagChanges  `json:"tags,omitempty"`
+}
+
+// FieldChange from one value to another
+type FieldChange struct {
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// TagChanges of the tags added to and removed from a mood
+type TagChanges struct {
+	Added   []AuditTag `json:"added"`
+	Removed []AuditTag `json:"removed"`
+}
+
+// AuditTag as recorded in the audit history, without the mood it belongs to
+type AuditTag struct {
+	Type      string `json:"type"`
+	SpotifyID string `json:"spotify_id"`
+}
+
+// Value for storing the changes in the DB
+func (c AuditChanges) Value() (driver.Value, error) {
+	b, err := json.Marshal(c)
+	return string(b), err
+}
+
+// Scan the changes from the DB
+func (c *AuditChanges) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case []byte:
+		return json.Unmarshal(value, c)
+	case string:
+		return json.Unmarshal([]byte(value), c)
+	}
+
+	return fmt.Errorf("unsupported audit changes type: %T", src)
+}
+
+// AuditRepository for interacting with mood audit data
+type AuditRepository interface {
+	// FindByMood lists the audit entries of the given mood, from the latest
+	FindByMood(mood *Mood) ([]*AuditEntry, error)
+	// Save appends the given entry to the audit history
+	Save(entry *AuditEntry) error
+}
+
+// auditState of a mood, which its audited changes are compared by
+type auditState struct {
+	Name  string
+	Color string
+	Tags  []AuditTag
+}
+
+// auditState of the mood as it is right now
+func (m *Mood) auditState() auditState {
+	tags := make([]AuditTag, 0, len(m.Tags))
+	for _, tag := range m.Tags {
+		tags = append(tags, AuditTag{Type: tag.Type, SpotifyID: tag.SpotifyID})
+	}
+
+	return auditState{Name: m.Name, Color: m.Color, Tags: tags}
+}
+
+// diffAudit between the given states, where the zero state stands for a mood which did not exist
+func diffAudit(before, after auditState) AuditChanges {
+	var changes AuditChanges
+	if before.Name != after.Name {
+		changes.Name = &FieldChange{Before: before.Name, After: after.Name}
+	}
+	if before.Color != after.Color {
+		changes.Color = &FieldChange{Before: before.Color, After: after.Color}
+	}
+
+	added := subtractTags(after.Tags, before.Tags)
+	removed := subtractTags(before.Tags, after.Tags)
+	if len(added) > 0 || len(removed) > 0 {
+		changes.Tags = &TagChanges{Added: added, Removed: removed}
+	}
+
+	return changes
+}
+
+// subtractTags leaves the tags of a which are not in b
+func subtractTags(a, b []AuditTag) []AuditTag {
+	seen := make(map[AuditTag]bool, len(b))
+	for _, tag := range b {
+		seen[tag] = true
+	}
+
+	left := make([]AuditTag, 0)
+	for _, tag := range a {
+		if !seen[tag] {
+			left = append(left, tag)
+		}
+	}
+
+	return left
+}
+
+// GetHistory lists the audit entries of the given mood, from the latest
+func (s *MoodService) GetHistory(mood *Mood) ([]*AuditEntry, error) {
+	return s.audit.FindByMood(mood)
+}
+
+// record an audit entry for the given mood, made by the given user through the API or by the app itself when nil.
+// The change is already made by then, so a failure to record it is only logged rather than failing the change.
+func (s *MoodService) record(mood *Mood, action, source string, actor *User, changes AuditChanges) {
+	entry := &AuditEntry{
+		MoodID:  mood.ID,
+		Source:  source,
+		Action:  action,
+		Changes: changes,
+	}
+	if actor != nil {
+		entry.ActorID = &actor.ID
+	}
+
+	if err := s.audit.Save(entry); err != nil {
+		log.Printf("Failed to record audit entry '%s' for mood (ID: %d): %v", action, mood.ID, err)
+	}
+}
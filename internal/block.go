@@ -0,0 +1,168 @@
+package internal
+
+import (
+	"log"
+	"time"
+)
+
+// Block types, referring to the kind of spotify item kept out of playlists
+const (
+	BlockTypeArtist = "artist"
+	BlockTypeTrack  = "track"
+)
+
+// Block keeps an artist or track out of generated playlists, either of every mood of a user or of a single mood
+type Block struct {
+	ID        uint      `gorm:"primary_key" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UserID    uint      `gorm:"not null;unique_index:idx_blocks_item" json:"-"`
+	// MoodID of the mood the block applies to, zero when it applies to all of the user's moods
+	MoodID    uint   `gorm:"not null;unique_index:idx_blocks_item" json:"mood_id,omitempty"`
+	Type      string `gorm:"size:16;not null;unique_index:idx_blocks_item" json:"type"`
+	SpotifyID string `gorm:"not null;unique_index:idx_blocks_item" json:"spotify_id"`
+}
+
+// BlockRepository for interacting with blocklist data
+type BlockRepository interface {
+	// FindByUser lists the blocks applying to all moods of the given user
+	FindByUser(user *User) ([]*Block, error)
+	// FindByMood lists the blocks applying only to the given mood
+	FindByMood(mood *Mood) ([]*Block, error)
+	// FindForMood lists every block applying to the given mood, including the user level ones
+	FindForMood(mood *Mood) ([]*Block, error)
+	// Save persists the given block unless it already exists
+	Save(block *Block) error
+	// Remove the block matching the given block's user, mood, type and spotify ID
+	Remove(block *Block) error
+}
+
+// BlockService for managing user and mood blocklists
+type BlockService struct {
+	r     BlockRepository
+	moods MoodRepository
+	q     QueueService
+}
+
+// NewBlockService constructor
+func NewBlockService(r BlockRepository, moods MoodRepository, q QueueService) *BlockService {
+	return &BlockService{
+		r:     r,
+		moods: moods,
+		q:     q,
+	}
+}
+
+// GetForUser lists the blocks applying to all moods of the given user
+func (s *BlockService) GetForUser(user *User) ([]*Block, error) {
+	return s.r.FindByUser(user)
+}
+
+// GetForMood lists the blocks applying only to the given mood
+func (s *BlockService) GetForMood(mood *Mood) ([]*Block, error) {
+	return s.r.FindByMood(mood)
+}
+
+// BlockForUser keeps the given item out of all playlists of the given user
+func (s *BlockService) BlockForUser(user *User, blockType, spotifyID string) (*Block, error) {
+	block := &Block{UserID: user.ID, Type: blockType, SpotifyID: spotifyID}
+	if err := s.r.Save(block); err != nil {
+		return nil, err
+	}
+
+	s.refreshUserPlaylists(user)
+	return block, nil
+}
+
+// UnblockForUser allows the given item back into all playlists of the given user
+func (s *BlockService) UnblockForUser(user *User, blockType, spotifyID string) error {
+	if err := s.r.Remove(&Block{UserID: user.ID, Type: blockType, SpotifyID: spotifyID}); err != nil {
+		return err
+	}
+
+	s.refreshUserPlaylists(user)
+	return nil
+}
+
+// BlockForMood keeps the given item out of the playlist of the given mood
+func (s *BlockService) BlockForMood(mood *Mood, blockType, spotifyID string) (*Block, error) {
+	block := &Block{UserID: mood.UserID, MoodID: mood.ID, Type: blockType, SpotifyID: spotifyID}
+	if err := s.r.Save(block); err != nil {
+		return nil, err
+	}
+
+	s.refreshPlaylist(mood)
+	return block, nil
+}
+
+// UnblockForMood allows the given item back into the playlist of the given mood
+func (s *BlockService) UnblockForMood(mood *Mood, blockType, spotifyID string) error {
+	if err := s.r.Remove(&Block{UserID: mood.UserID, MoodID: mood.ID, Type: blockType, SpotifyID: spotifyID}); err != nil {
+		return err
+	}
+
+	s.refreshPlaylist(mood)
+	return nil
+}
+
+// refreshUserPlaylists adds tasks to regenerate the playlists of all moods of the given user.
+// The blocks are already saved by then, so failures are only logged and the other moods are still refreshed.
+func (s *BlockService) refreshUserPlaylists(user *User) {
+	moods, err := s.moods.FindByUser(user)
+	if err != nil {
+		log.Printf("Failed to find moods to refresh for user (ID: %d): %v", user.ID, err)
+		return
+	}
+
+	for _, mood := range moods {
+		s.refreshPlaylist(mood)
+	}
+}
+
+// refreshPlaylist adds a task to regenerate the playlist of the given mood, if it has one yet.
+// Failures are only logged, the playlist then keeps its current tracks until it is refreshed again.
+func (s *BlockService) refreshPlaylist(mood *Mood) {
+	if mood.PlaylistID == "" {
+		return
+	}
+	if err := s.q.RefreshPlaylist(mood); err != nil {
+		log.Printf("Failed to queue playlist refresh for mood (ID: %d): %v", mood.ID, err)
+	}
+}
+
+// blocklist of artist and track IDs to keep out of a playlist
+type blocklist struct {
+	artists map[string]bool
+	tracks  map[string]bool
+}
+
+func newBlocklist(blocks []*Block) *blocklist {
+	l := &blocklist{
+		artists: make(map[string]bool),
+		tracks:  make(map[string]bool),
+	}
+
+	for _, block := range blocks {
+		switch block.Type {
+		case BlockTypeArtist:
+			l.artists[block.SpotifyID] = true
+		case BlockTypeTrack:
+			l.tracks[block.SpotifyID] = true
+		}
+	}
+
+	return l
+}
+
+// blocks checks whether the given track or any of its artists are blocked
+func (l *blocklist) blocks(track *SpotifyTrack) bool {
+	if l.tracks[track.ID] {
+		return true
+	}
+
+	for _, artist := range track.Artists {
+		if l.artists[artist.ID] {
+			return true
+		}
+	}
+	return false
+}
@@ -17,6 +17,12 @@ func ViperInit() {
 	viper.AutomaticEnv()
 	// Defaults
 	viper.SetDefault("port", 80)
+	viper.SetDefault("scheduler.history_interval", "15m")
+	viper.SetDefault("scheduler.active_within", "720h")
+	viper.SetDefault("scheduler.drift_interval", "1h")
+	viper.SetDefault("scheduler.sweep_interval", "30m")
+	viper.SetDefault("scheduler.sweep_after", "1h")
+	viper.SetDefault("moods.undo_window", "24h")
 
 	initFlags()
 
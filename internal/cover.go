@@ -0,0 +1,30 @@
+package internal
+
+import "github.com/flexicon/spotimoods-go/internal/cover"
+
+// coverOptions to render the playlist cover of the mood with
+func (m *Mood) coverOptions() cover.Options {
+	return cover.Options{
+		Name:     m.Name,
+		Color:    m.Color,
+		Initials: m.CoverInitials,
+	}
+}
+
+// UploadCoverForMood renders the playlist cover of the mood by the given ID and uploads it to spotify
+func (s *MoodService) UploadCoverForMood(moodID uint, token *SpotifyToken) error {
+	mood, err := s.Find(moodID)
+	if err != nil {
+		return err
+	}
+	if mood.PlaylistID == "" {
+		return ErrNoPlaylist
+	}
+
+	jpeg, err := cover.Render(mood.coverOptions())
+	if err != nil {
+		return err
+	}
+
+	return s.spotify.UploadPlaylistCover(token, mood.PlaylistID, jpeg)
+}
@@ -0,0 +1,211 @@
+// Package cover renders playlist cover images for moods
+package cover
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	// size of the square cover in pixels
+	size = 640
+	// padding between the name and the edges of the cover
+	padding = 40
+	// nameSize and initialsSize are font sizes in points at 72 DPI, so equal to pixels
+	nameSize     = 44
+	initialsSize = 260
+
+	// MaxEncodedSize spotify accepts for a base64 encoded cover
+	MaxEncodedSize = 256 << 10
+)
+
+var (
+	fontOnce sync.Once
+	boldFont *opentype.Font
+	fontErr  error
+)
+
+// Options of a cover to render
+type Options struct {
+	Name  string
+	Color string
+	// Initials of the name are drawn large in the middle of the cover when set
+	Initials bool
+}
+
+// Render a JPEG cover with a diagonal gradient of the given color, the name along the bottom and optional initials.
+// The quality is lowered for as long as the cover would be too large for spotify once encoded.
+func Render(opts Options) ([]byte, error) {
+	base, err := parseHexColor(opts.Color)
+	if err != nil {
+		return nil, err
+	}
+
+	img := gradient(base)
+	text := textColor(base)
+
+	if opts.Initials {
+		if err := drawCentered(img, initials(opts.Name), initialsSize, text); err != nil {
+			return nil, err
+		}
+	}
+	if err := drawName(img, opts.Name, text); err != nil {
+		return nil, err
+	}
+
+	for quality := 90; quality > 0; quality -= 10 {
+		var b bytes.Buffer
+		if err := jpeg.Encode(&b, img, &jpeg.Options{Quality: quality}); err != nil {
+			return nil, err
+		}
+		if base64.StdEncoding.EncodedLen(b.Len()) <= MaxEncodedSize {
+			return b.Bytes(), nil
+		}
+	}
+
+	return nil, fmt.Errorf("cover does not fit in %d bytes", MaxEncodedSize)
+}
+
+// gradient from the given color in the top left corner to a darker shade of it in the bottom right one
+func gradient(base color.RGBA) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	dark := shade(base, 0.45)
+
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			t := float64(x+y) / float64(2*(size-1))
+			img.SetRGBA(x, y, mix(base, dark, t))
+		}
+	}
+
+	return img
+}
+
+// drawCentered draws the given text in the middle of the image
+func drawCentered(img *image.RGBA, text string, points float64, c color.Color) error {
+	face, err := newFace(points)
+	if err != nil {
+		return err
+	}
+	defer face.Close()
+
+	d := &font.Drawer{Dst: img, Src: image.NewUniform(c), Face: face}
+	width := d.MeasureString(text)
+	capHeight := face.Metrics().CapHeight
+
+	d.Dot = fixed.Point26_6{
+		X: (fixed.I(size) - width) / 2,
+		Y: (fixed.I(size) + capHeight) / 2,
+	}
+	d.DrawString(text)
+
+	return nil
+}
+
+// drawName along the bottom of the image, cut short with an ellipsis when wider than the cover
+func drawName(img *image.RGBA, name string, c color.Color) error {
+	face, err := newFace(nameSize)
+	if err != nil {
+		return err
+	}
+	defer face.Close()
+
+	d := &font.Drawer{Dst: img, Src: image.NewUniform(c), Face: face}
+	maxWidth := fixed.I(size - 2*padding)
+
+	text := strings.TrimSpace(name)
+	if d.MeasureString(text) > maxWidth {
+		runes := []rune(text)
+		for len(runes) > 0 && d.MeasureString(string(runes)+"…") > maxWidth {
+			runes = runes[:len(runes)-1]
+		}
+		text = strings.TrimSpace(string(runes)) + "…"
+	}
+
+	d.Dot = fixed.P(padding, size-padding)
+	d.DrawString(text)
+
+	return nil
+}
+
+func newFace(points float64) (font.Face, error) {
+	fontOnce.Do(func() {
+		boldFont, fontErr = opentype.Parse(gobold.TTF)
+	})
+	if fontErr != nil {
+		return nil, fontErr
+	}
+
+	return opentype.NewFace(boldFont, &opentype.FaceOptions{Size: points, DPI: 72, Hinting: font.HintingFull})
+}
+
+// initials of the first two words of the given name
+func initials(name string) string {
+	var letters []rune
+	for _, word := range strings.Fields(name) {
+		r, _ := utf8.DecodeRuneInString(word)
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			letters = append(letters, unicode.ToUpper(r))
+		}
+		if len(letters) == 2 {
+			break
+		}
+	}
+
+	return string(letters)
+}
+
+// textColor which stays readable on top of the given color
+func textColor(base color.RGBA) color.Color {
+	luminance := 0.299*float64(base.R) + 0.587*float64(base.G) + 0.114*float64(base.B)
+	if luminance > 160 {
+		return color.RGBA{R: 24, G: 24, B: 24, A: 255}
+	}
+	return color.White
+}
+
+// shade the given color towards black by the given amount
+func shade(c color.RGBA, amount float64) color.RGBA {
+	return mix(c, color.RGBA{A: 255}, amount)
+}
+
+// mix two colors, where t of 0 is the first color and 1 is the second one
+func mix(a, b color.RGBA, t float64) color.RGBA {
+	channel := func(x, y uint8) uint8 {
+		return uint8(float64(x) + (float64(y)-float64(x))*t)
+	}
+
+	return color.RGBA{R: channel(a.R, b.R), G: channel(a.G, b.G), B: channel(a.B, b.B), A: 255}
+}
+
+// parseHexColor in either the #rrggbb or the short #rgb form
+func parseHexColor(hex string) (color.RGBA, error) {
+	hex = strings.TrimPrefix(hex, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) != 6 {
+		return color.RGBA{}, fmt.Errorf("invalid hex color: %s", hex)
+	}
+
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("invalid hex color: %s", hex)
+	}
+
+	return color.RGBA{R: uint8(value >> 16), G: uint8(value >> 8), B: uint8(value), A: 255}, nil
+}
@@ -0,0 +1,24 @@
+package db
+
+import (
+	"github.com/flexicon/spotimoods-go/internal"
+	"github.com/jinzhu/gorm"
+)
+
+// AuditRepository for interacting with mood audit data in the DB
+type AuditRepository struct {
+	db *gorm.DB
+}
+
+// FindByMood lists the audit entries of the given mood, from the latest
+func (r *AuditRepository) FindByMood(mood *internal.Mood) ([]*internal.AuditEntry, error) {
+	var entries []*internal.AuditEntry
+	err := r.db.Where("mood_id = ?", mood.ID).Order("id DESC").Find(&entries).Error
+
+	return entries, err
+}
+
+// Save appends the given entry to the audit history
+func (r *AuditRepository) Save(entry *internal.AuditEntry) error {
+	return r.db.Create(entry).Error
+}
@@ -0,0 +1,59 @@
+package db
+
+import (
+	"github.com/flexicon/spotimoods-go/internal"
+	"github.com/jinzhu/gorm"
+)
+
+// BlockRepository for interacting with blocklist data in the DB
+type BlockRepository struct {
+	db *gorm.DB
+}
+
+// FindByUser lists the blocks applying to all moods of the given user
+func (r *BlockRepository) FindByUser(user *internal.User) ([]*internal.Block, error) {
+	var blocks []*internal.Block
+	err := r.db.Where("user_id = ? AND mood_id = 0", user.ID).Order("created_at").Find(&blocks).Error
+
+	return blocks, err
+}
+
+// FindByMood lists the blocks applying only to the given mood
+func (r *BlockRepository) FindByMood(mood *internal.Mood) ([]*internal.Block, error) {
+	var blocks []*internal.Block
+	err := r.db.Where("mood_id = ?", mood.ID).Order("created_at").Find(&blocks).Error
+
+	return blocks, err
+}
+
+// FindForMood lists every block applying to the given mood, including the user level ones
+func (r *BlockRepository) FindForMood(mood *internal.Mood) ([]*internal.Block, error) {
+	var blocks []*internal.Block
+	err := r.db.Where("user_id = ? AND mood_id IN (0, ?)", mood.UserID, mood.ID).Find(&blocks).Error
+
+	return blocks, err
+}
+
+// Save persists the given block unless it already exists
+func (r *BlockRepository) Save(block *internal.Block) error {
+	return r.db.Where(
+		"user_id = ? AND mood_id = ? AND type = ? AND spotify_id = ?",
+		block.UserID, block.MoodID, block.Type, block.SpotifyID,
+	).FirstOrCreate(block).Error
+}
+
+// Remove the block matching the given block's user, mood, type and spotify ID
+func (r *BlockRepository) Remove(block *internal.Block) error {
+	query := r.db.Where(
+		"user_id = ? AND mood_id = ? AND type = ? AND spotify_id = ?",
+		block.UserID, block.MoodID, block.Type, block.SpotifyID,
+	).Delete(internal.Block{})
+	if query.Error != nil {
+		return query.Error
+	}
+	if query.RowsAffected == 0 {
+		return internal.ErrNotFound
+	}
+
+	return nil
+}
@@ -27,6 +27,18 @@ func NewDB() *gorm.DB {
 	}
 
 	autoMigrate(db)
+	if err := migrateArtistTags(db); err != nil {
+		log.Fatalln("Failed to migrate artist tags:", err)
+	}
+	if err := migratePublicMoods(db); err != nil {
+		log.Fatalln("Failed to migrate mood visibility:", err)
+	}
+	if err := migrateMoodPositions(db); err != nil {
+		log.Fatalln("Failed to migrate mood positions:", err)
+	}
+	if err := migratePlayedAtPrecision(db); err != nil {
+		log.Fatalln("Failed to migrate play time precision:", err)
+	}
 
 	return db
 }
@@ -37,12 +49,47 @@ func autoMigrate(d *gorm.DB) {
 		&internal.SpotifyToken{},
 		&internal.Mood{},
 		&internal.Tag{},
+		&internal.Block{},
+		&internal.Play{},
+		&internal.Snapshot{},
+		&internal.AuditEntry{},
 	)
 }
 
+// migrateArtistTags moves tags from the legacy artist only tags table into typed mood tags and drops it
+func migrateArtistTags(d *gorm.DB) error {
+	if !d.HasTable("tags") {
+		return nil
+	}
+
+	if err := d.Exec(
+		"INSERT IGNORE INTO mood_tags (mood_id, type, spotify_id) SELECT mood_id, ?, artist_id FROM tags",
+		internal.TagTypeArtist,
+	).Error; err != nil {
+		return err
+	}
+
+	return d.DropTable("tags").Error
+}
+
 func newConfig() *config {
 	return &config{
 		connectionURI: viper.GetString("database.url"),
 		verbose:       viper.GetBool("database.verbose"),
 	}
 }
+
+// migratePublicMoods marks moods which predate the public flag as public, as every playlist used to be created public
+func migratePublicMoods(d *gorm.DB) error {
+	return d.Exec("UPDATE moods SET public = TRUE WHERE public IS NULL").Error
+}
+
+// migrateMoodPositions keeps moods which predate positions in the order they were created in, after their ID
+func migrateMoodPositions(d *gorm.DB) error {
+	return d.Exec("UPDATE moods SET position = id WHERE position = 0").Error
+}
+
+// migratePlayedAtPrecision keeps the milliseconds of play times, which auto migrations never add to an existing column
+func migratePlayedAtPrecision(d *gorm.DB) error {
+	return d.Model(&internal.Play{}).ModifyColumn("played_at", "datetime(3) NOT NULL").Error
+}
@@ -1,10 +1,25 @@
 package db
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/flexicon/spotimoods-go/internal"
 	"github.com/jinzhu/gorm"
 )
 
+// moodSortColumns of every mood sort order
+var moodSortColumns = map[string]string{
+	internal.MoodSortPosition:  "position",
+	internal.MoodSortName:      "name",
+	internal.MoodSortCreatedAt: "created_at",
+	internal.MoodSortUpdatedAt: "updated_at",
+}
+
+// likeEscaper escapes the wildcards of a LIKE pattern
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
 // MoodRepository for interacting with mood data in the DB
 type MoodRepository struct {
 	db *gorm.DB
@@ -32,7 +47,40 @@ func (r *MoodRepository) FindByIDAndUser(id uint, user *internal.User) (*interna
 	return &mood, query.Error
 }
 
-// Remove mood by ID
+// FindByIDAndUserWithDeleted if it exists, even when it was deleted
+func (r *MoodRepository) FindByIDAndUserWithDeleted(id uint, user *internal.User) (*internal.Mood, error) {
+	var mood internal.Mood
+	query := r.db.Unscoped().Preload("Tags").Where("id = ? AND user_id = ?", id, user.ID).First(&mood)
+	if query.RecordNotFound() {
+		return nil, internal.ErrNotFound
+	}
+
+	return &mood, query.Error
+}
+
+// FindByPlaylistID the mood managing the given playlist if it exists, including a deleted mood which still has it
+func (r *MoodRepository) FindByPlaylistID(playlistID string) (*internal.Mood, error) {
+	var mood internal.Mood
+	query := r.db.Unscoped().Where("playlist_id = ? AND playlist_deleted_at IS NULL", playlistID).First(&mood)
+	if query.RecordNotFound() {
+		return nil, internal.ErrNotFound
+	}
+
+	return &mood, query.Error
+}
+
+// FindByShareToken a published mood if it exists
+func (r *MoodRepository) FindByShareToken(token string) (*internal.Mood, error) {
+	var mood internal.Mood
+	query := r.db.Preload("Tags").Where("share_token = ?", token).First(&mood)
+	if query.RecordNotFound() {
+		return nil, internal.ErrNotFound
+	}
+
+	return &mood, query.Error
+}
+
+// Remove mood by ID, keeping it marked as deleted
 func (r *MoodRepository) Remove(id uint) error {
 	query := r.db.Delete(internal.Mood{ID: id})
 	if query.RecordNotFound() {
@@ -42,6 +90,32 @@ func (r *MoodRepository) Remove(id uint) error {
 	return query.Error
 }
 
+// FindDeletedByUser moods of the given user deleted after the given time, from the latest
+func (r *MoodRepository) FindDeletedByUser(user *internal.User, after time.Time) ([]*internal.Mood, error) {
+	var moods []*internal.Mood
+	err := r.db.Unscoped().Preload("Tags").
+		Where("user_id = ? AND deleted_at IS NOT NULL AND deleted_at > ?", user.ID, after).
+		Order("deleted_at DESC").
+		Find(&moods).Error
+
+	return moods, err
+}
+
+// Restore the mood by ID and user, if it was deleted after the given time
+func (r *MoodRepository) Restore(id uint, user *internal.User, after time.Time) (*internal.Mood, error) {
+	query := r.db.Unscoped().Model(&internal.Mood{}).
+		Where("id = ? AND user_id = ? AND deleted_at IS NOT NULL AND deleted_at > ?", id, user.ID, after).
+		UpdateColumn("deleted_at", nil)
+	if query.Error != nil {
+		return nil, query.Error
+	}
+	if query.RowsAffected == 0 {
+		return nil, internal.ErrNotFound
+	}
+
+	return r.Find(id)
+}
+
 // Save upserts the given user into the DB
 func (r *MoodRepository) Save(mood *internal.Mood) error {
 	if r.db.NewRecord(mood) {
@@ -50,6 +124,18 @@ func (r *MoodRepository) Save(mood *internal.Mood) error {
 	return r.db.Save(&mood).Error
 }
 
+// SaveAll inserts all of the given moods at once, or none of them on failure
+func (r *MoodRepository) SaveAll(moods []*internal.Mood) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for _, mood := range moods {
+			if err := tx.Create(mood).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 // FindByUser all moods for a given user
 func (r *MoodRepository) FindByUser(user *internal.User) ([]*internal.Mood, error) {
 	var moods []*internal.Mood
@@ -58,12 +144,222 @@ func (r *MoodRepository) FindByUser(user *internal.User) ([]*internal.Mood, erro
 	return moods, err
 }
 
-// Update persists the given changeset to the given mood
-func (r *MoodRepository) Update(mood *internal.Mood, changes internal.Mood) error {
-	query := r.db.Model(&mood).Update(changes)
-	if query.RecordNotFound() {
-		return internal.ErrNotFound
+// FindPageByUser a page of moods of the given user matching the given query
+func (r *MoodRepository) FindPageByUser(user *internal.User, query internal.MoodQuery) ([]*internal.Mood, error) {
+	q := r.db.Preload("Tags").Where("user_id = ?", user.ID)
+
+	if query.HasPlaylist != nil {
+		if *query.HasPlaylist {
+			q = q.Where("playlist_id <> ''")
+		} else {
+			q = q.Where("playlist_id = ''")
+		}
+	}
+	if query.Color != "" {
+		q = q.Where("LOWER(color) = LOWER(?)", query.Color)
+	}
+	if query.Name != "" {
+		q = q.Where("name LIKE ?", "%"+likeEscaper.Replace(query.Name)+"%")
+	}
+	if query.ArtistID != "" {
+		q = q.Where(
+			"EXISTS (SELECT 1 FROM mood_tags WHERE mood_tags.mood_id = moods.id AND mood_tags.type = ? AND mood_tags.spotify_id = ?)",
+			internal.TagTypeArtist, query.ArtistID,
+		)
 	}
 
-	return query.Error
+	column := moodSortColumns[query.Sort]
+	if column == "" {
+		column = moodSortColumns[internal.MoodSortPosition]
+	}
+	op, dir := ">", "ASC"
+	pinnedOp, pinnedDir := "<", "DESC"
+	if query.Desc {
+		op, dir = "<", "DESC"
+		pinnedOp, pinnedDir = ">", "ASC"
+	}
+
+	// Keyset pagination continues right after the mood of the cursor, by its sort value and then by ID
+	if after := query.After; after != nil {
+		var value interface{} = after.Time
+		switch query.Sort {
+		case internal.MoodSortName:
+			value = after.Name
+		case internal.MoodSortPosition:
+			value = after.Position
+		}
+		keyset := fmt.Sprintf("(%[1]s %[2]s ? OR (%[1]s = ? AND id %[2]s ?))", column, op)
+		args := []interface{}{value, value, after.ID}
+
+		// Pinned moods come before all others, so the position only decides within the same pinned state
+		if query.Sort == internal.MoodSortPosition {
+			keyset = fmt.Sprintf("(pinned %s ? OR (pinned = ? AND %s))", pinnedOp, keyset)
+			args = append([]interface{}{after.Pinned, after.Pinned}, args...)
+		}
+		q = q.Where(keyset, args...)
+	}
+	if query.Sort == internal.MoodSortPosition {
+		q = q.Order("pinned " + pinnedDir)
+	}
+
+	var moods []*internal.Mood
+	err := q.Order(column + " " + dir).Order("id " + dir).Limit(query.Limit).Find(&moods).Error
+
+	return moods, err
+}
+
+// NextPosition after the last of the given user's moods
+func (r *MoodRepository) NextPosition(user *internal.User) (int, error) {
+	var row struct {
+		Position int
+	}
+	err := r.db.Unscoped().Model(&internal.Mood{}).
+		Select("COALESCE(MAX(position), 0) AS position").
+		Where("user_id = ?", user.ID).
+		Scan(&row).Error
+
+	return row.Position + 1, err
+}
+
+// Reorder the moods of the given user by the given IDs, which must list every one of their moods exactly once
+func (r *MoodRepository) Reorder(user *internal.User, ids []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var current []uint
+		err := tx.Set("gorm:query_option", "FOR UPDATE").Model(&internal.Mood{}).
+			Where("user_id = ?", user.ID).
+			Pluck("id", &current).Error
+		if err != nil {
+			return err
+		}
+
+		if len(ids) != len(current) {
+			return internal.ErrInvalidOrder
+		}
+		owned := make(map[uint]bool, len(current))
+		for _, id := range current {
+			owned[id] = true
+		}
+		for _, id := range ids {
+			if !owned[id] {
+				return internal.ErrInvalidOrder
+			}
+			// Every mood may only be listed once
+			delete(owned, id)
+		}
+
+		for i, id := range ids {
+			if err := tx.Model(&internal.Mood{ID: id}).UpdateColumn("position", i+1).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Update persists only the given fields of the mood, leaving all other columns as they are.
+// Fields are named as in the mood struct, where embedded structs like the features are written as a whole.
+func (r *MoodRepository) Update(mood *internal.Mood, fields ...string) error {
+	changes := make(map[string]interface{})
+	for _, field := range r.db.NewScope(mood).Fields() {
+		if !field.IsNormal || field.IsIgnored {
+			continue
+		}
+		for _, name := range fields {
+			if field.Names[0] == name {
+				changes[field.DBName] = field.Field.Interface()
+			}
+		}
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	return r.db.Model(mood).Set("gorm:save_associations", false).Updates(changes).Error
+}
+
+// ReplaceTags of the given mood with the given set of tags
+func (r *MoodRepository) ReplaceTags(mood *internal.Mood, tags []internal.Tag) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("mood_id = ?", mood.ID).Delete(internal.Tag{}).Error; err != nil {
+			return err
+		}
+
+		for i := range tags {
+			tags[i].MoodID = mood.ID
+			if err := tx.Create(&tags[i]).Error; err != nil {
+				return err
+			}
+		}
+
+		mood.Tags = tags
+		return nil
+	})
+}
+
+// UpdateSync persists the playlist sync details of the given mood
+func (r *MoodRepository) UpdateSync(mood *internal.Mood) error {
+	return r.db.Model(mood).UpdateColumns(map[string]interface{}{
+		"track_count": mood.TrackCount,
+		"duration_ms": mood.DurationMs,
+		"synced_at":   mood.SyncedAt,
+	}).Error
+}
+
+// CountTags per tag type for each of the given moods
+func (r *MoodRepository) CountTags(moodIDs []uint) (map[uint]map[string]int, error) {
+	var rows []struct {
+		MoodID uint
+		Type   string
+		Count  int
+	}
+	err := r.db.Table("mood_tags").
+		Select("mood_id, type, COUNT(*) AS count").
+		Where("mood_id IN (?)", moodIDs).
+		Group("mood_id, type").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uint]map[string]int)
+	for _, row := range rows {
+		if counts[row.MoodID] == nil {
+			counts[row.MoodID] = make(map[string]int)
+		}
+		counts[row.MoodID][row.Type] = row.Count
+	}
+
+	return counts, nil
+}
+
+// FindWithoutPlaylist moods created and last queued for a playlist before the given time, which still have none
+func (r *MoodRepository) FindWithoutPlaylist(before time.Time) ([]*internal.Mood, error) {
+	var moods []*internal.Mood
+	err := r.db.Where("playlist_id = '' AND created_at < ?", before).
+		Where("playlist_queued_at IS NULL OR playlist_queued_at < ?", before).
+		Find(&moods).Error
+
+	return moods, err
+}
+
+// FindUndeletedPlaylists of moods deleted before the given time, whose playlist was never unfollowed
+func (r *MoodRepository) FindUndeletedPlaylists(before time.Time) ([]*internal.Mood, error) {
+	var moods []*internal.Mood
+	err := r.db.Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+		Where("playlist_id <> '' AND playlist_deleted_at IS NULL").
+		Find(&moods).Error
+
+	return moods, err
+}
+
+// MarkPlaylistQueued at the given time for the given mood
+func (r *MoodRepository) MarkPlaylistQueued(mood *internal.Mood, at time.Time) error {
+	mood.PlaylistQueuedAt = &at
+	return r.db.Model(mood).UpdateColumn("playlist_queued_at", at).Error
+}
+
+// MarkPlaylistDeleted at the given time for the given, possibly deleted, mood
+func (r *MoodRepository) MarkPlaylistDeleted(id uint, at time.Time) error {
+	return r.db.Unscoped().Model(&internal.Mood{ID: id}).UpdateColumn("playlist_deleted_at", at).Error
 }
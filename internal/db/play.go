@@ -0,0 +1,103 @@
+package db
+
+import (
+	"time"
+
+	"github.com/flexicon/spotimoods-go/internal"
+	"github.com/jinzhu/gorm"
+)
+
+// PlayRepository for interacting with listening history data in the DB
+type PlayRepository struct {
+	db *gorm.DB
+}
+
+// FindByUser lists plays of the given user played before the given time, from the latest
+func (r *PlayRepository) FindByUser(user *internal.User, before time.Time, limit int) ([]*internal.Play, error) {
+	var plays []*internal.Play
+	err := r.db.Preload("Moods").
+		Where("user_id = ? AND played_at < ?", user.ID, before).
+		Order("played_at DESC").
+		Limit(limit).
+		Find(&plays).Error
+
+	return plays, err
+}
+
+// LatestPlayedAt of the given user, zero when nothing was stored yet
+func (r *PlayRepository) LatestPlayedAt(user *internal.User) (time.Time, error) {
+	var play internal.Play
+	query := r.db.Where("user_id = ?", user.ID).Order("played_at DESC").First(&play)
+	if query.RecordNotFound() {
+		return time.Time{}, nil
+	}
+
+	return play.PlayedAt, query.Error
+}
+
+// SaveAll stores the given plays along with their moods, skipping plays which are already stored
+func (r *PlayRepository) SaveAll(plays []*internal.Play) (int, error) {
+	saved := 0
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for _, play := range plays {
+			// An overlap with a previous sync must not fail the whole batch on the unique played at key
+			query := tx.Set("gorm:insert_modifier", "IGNORE").Set("gorm:save_associations", false).Create(play)
+			if query.Error != nil {
+				return query.Error
+			}
+			if query.RowsAffected == 0 {
+				continue
+			}
+			saved++
+
+			for _, mood := range play.Moods {
+				if err := tx.Exec("INSERT INTO play_moods (play_id, mood_id) VALUES (?, ?)", play.ID, mood.ID).Error; err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return saved, nil
+}
+
+// CountByMood counts the plays attributed to the given mood per day since the given time
+func (r *PlayRepository) CountByMood(mood *internal.Mood, since time.Time) ([]internal.PlayCount, error) {
+	counts := make([]internal.PlayCount, 0)
+	err := r.db.Table("plays").
+		Select("DATE_FORMAT(plays.played_at, '%Y-%m-%d') AS date, COUNT(*) AS count").
+		Joins("JOIN play_moods ON play_moods.play_id = plays.id").
+		Where("play_moods.mood_id = ? AND plays.played_at >= ?", mood.ID, since).
+		Group("date").
+		Order("date").
+		Scan(&counts).Error
+
+	return counts, err
+}
+
+// CountTotalByMoods counts all plays attributed to each of the given moods
+func (r *PlayRepository) CountTotalByMoods(moodIDs []uint) (map[uint]int, error) {
+	var rows []struct {
+		MoodID uint
+		Count  int
+	}
+	err := r.db.Table("play_moods").
+		Select("mood_id, COUNT(*) AS count").
+		Where("mood_id IN (?)", moodIDs).
+		Group("mood_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uint]int)
+	for _, row := range rows {
+		counts[row.MoodID] = row.Count
+	}
+
+	return counts, nil
+}
@@ -24,3 +24,23 @@ func (p *RepositoryProvider) User() internal.UserRepository {
 func (p *RepositoryProvider) Mood() internal.MoodRepository {
 	return &MoodRepository{db: p.db}
 }
+
+// Block returns a new BlockRepository
+func (p *RepositoryProvider) Block() internal.BlockRepository {
+	return &BlockRepository{db: p.db}
+}
+
+// Play returns a new PlayRepository
+func (p *RepositoryProvider) Play() internal.PlayRepository {
+	return &PlayRepository{db: p.db}
+}
+
+// Snapshot returns a new SnapshotRepository
+func (p *RepositoryProvider) Snapshot() internal.SnapshotRepository {
+	return &SnapshotRepository{db: p.db}
+}
+
+// Audit returns a new AuditRepository
+func (p *RepositoryProvider) Audit() internal.AuditRepository {
+	return &AuditRepository{db: p.db}
+}
@@ -0,0 +1,46 @@
+package db
+
+import (
+	"github.com/flexicon/spotimoods-go/internal"
+	"github.com/jinzhu/gorm"
+)
+
+// SnapshotRepository for interacting with mood snapshot data in the DB
+type SnapshotRepository struct {
+	db *gorm.DB
+}
+
+// FindByMood lists the snapshots of the given mood, from the latest
+func (r *SnapshotRepository) FindByMood(mood *internal.Mood) ([]*internal.Snapshot, error) {
+	var snapshots []*internal.Snapshot
+	err := r.db.Where("mood_id = ?", mood.ID).Order("id DESC").Find(&snapshots).Error
+
+	return snapshots, err
+}
+
+// FindForMood the snapshot by the given ID, if it belongs to the given mood
+func (r *SnapshotRepository) FindForMood(id uint, mood *internal.Mood) (*internal.Snapshot, error) {
+	var snapshot internal.Snapshot
+	query := r.db.Where("id = ? AND mood_id = ?", id, mood.ID).First(&snapshot)
+	if query.RecordNotFound() {
+		return nil, internal.ErrNotFound
+	}
+
+	return &snapshot, query.Error
+}
+
+// Save persists the given snapshot
+func (r *SnapshotRepository) Save(snapshot *internal.Snapshot) error {
+	return r.db.Create(snapshot).Error
+}
+
+// Prune the snapshots of the given mood down to the given amount of latest ones
+func (r *SnapshotRepository) Prune(mood *internal.Mood, keep int) error {
+	// MySQL does not allow a limit in a subquery of the table being deleted from, unless it is wrapped in another one
+	return r.db.Exec(
+		`DELETE FROM mood_snapshots WHERE mood_id = ? AND id NOT IN (
+			SELECT id FROM (SELECT id FROM mood_snapshots WHERE mood_id = ? ORDER BY id DESC LIMIT ?) AS latest
+		)`,
+		mood.ID, mood.ID, keep,
+	).Error
+}
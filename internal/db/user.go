@@ -1,6 +1,8 @@
 package db
 
 import (
+	"time"
+
 	"github.com/flexicon/spotimoods-go/internal"
 	"github.com/jinzhu/gorm"
 )
@@ -21,6 +23,16 @@ func (r *UserRepository) FindByEmail(email string) (*internal.User, error) {
 	return &user, query.Error
 }
 
+// FindActive users, whose spotify token was refreshed since the given time
+func (r *UserRepository) FindActive(since time.Time) ([]*internal.User, error) {
+	var users []*internal.User
+	err := r.db.Joins("JOIN spotify_tokens ON spotify_tokens.user_id = users.id").
+		Where("spotify_tokens.updated_at >= ?", since).
+		Find(&users).Error
+
+	return users, err
+}
+
 // FindTokenByUser attempts to retrieve a SpotifyToken for the given user ID
 func (r *UserRepository) FindTokenByUser(userID uint) (*internal.SpotifyToken, error) {
 	var token internal.SpotifyToken
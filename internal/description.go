@@ -0,0 +1,102 @@
+package internal
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// maxDescriptionLength spotify accepts for playlist descriptions
+const maxDescriptionLength = 300
+
+// Description template placeholders
+const (
+	DescriptionName    = "{name}"
+	DescriptionArtists = "{artists}"
+	DescriptionDate    = "{date}"
+)
+
+// hasPlaceholders tells whether the given description template uses any of the placeholders
+func hasPlaceholders(description string) bool {
+	for _, placeholder := range []string{DescriptionName, DescriptionArtists, DescriptionDate} {
+		if strings.Contains(description, placeholder) {
+			return true
+		}
+	}
+	return false
+}
+
+// playlistDetails of the given mood, along with its rendered description.
+// Moods without a description leave the one of their playlist as it is, as it may have been written in spotify.
+func (s *MoodService) playlistDetails(mood *Mood, token *SpotifyToken) (PlaylistDetails, error) {
+	details := mood.PlaylistDetails()
+	if mood.Description == "" {
+		return details, nil
+	}
+
+	description, err := s.renderDescription(mood, token)
+	if err != nil {
+		return details, err
+	}
+	details.Description = &description
+
+	return details, nil
+}
+
+// renderDescription fills in the placeholders of the description template of the given mood,
+// cut down to the length spotify accepts
+func (s *MoodService) renderDescription(mood *Mood, token *SpotifyToken) (string, error) {
+	description := mood.Description
+
+	if strings.Contains(description, DescriptionArtists) {
+		ids := IDsByType(mood.Tags, TagTypeArtist)
+		artists, _, err := s.spotify.GetArtistsByIDs(token, ids)
+		if err != nil {
+			return "", err
+		}
+
+		names := make([]string, 0, len(ids))
+		for _, id := range ids {
+			if artist, ok := artists[id]; ok {
+				names = append(names, artist.Name)
+			}
+		}
+		description = strings.ReplaceAll(description, DescriptionArtists, strings.Join(names, ", "))
+	}
+
+	date := ""
+	if mood.SyncedAt != nil {
+		date = mood.SyncedAt.Format("2006-01-02")
+	}
+	description = strings.NewReplacer(DescriptionName, mood.Name, DescriptionDate, date).Replace(description)
+
+	// Spotify rejects descriptions with line breaks
+	description = strings.Join(strings.Fields(description), " ")
+	for utf8.RuneCountInString(description) > maxDescriptionLength {
+		_, size := utf8.DecodeLastRuneInString(description)
+		description = description[:len(description)-size]
+	}
+
+	return description, nil
+}
+
+// UpdatePlaylistForMood pushes the current details of the mood by the given ID to its playlist.
+// The playlist description is cleared when asked to, as long as the mood still has none.
+func (s *MoodService) UpdatePlaylistForMood(moodID uint, clearDescription bool, token *SpotifyToken) error {
+	mood, err := s.Find(moodID)
+	if err != nil {
+		return err
+	}
+	if mood.PlaylistID == "" {
+		return ErrNoPlaylist
+	}
+
+	details, err := s.playlistDetails(mood, token)
+	if err != nil {
+		return err
+	}
+	if clearDescription && mood.Description == "" {
+		details.Description = new(string)
+	}
+
+	return s.spotify.UpdatePlaylist(token, mood.PlaylistID, details)
+}
@@ -0,0 +1,220 @@
+package internal
+
+import (
+	"html"
+	"log"
+	"time"
+)
+
+// Drift policies, deciding how moods are reconciled with playlists changed directly in spotify
+const (
+	// DriftPolicyPush overwrites the playlist with the state of the mood
+	DriftPolicyPush = "push"
+	// DriftPolicyPull takes the state of the playlist into the mood
+	DriftPolicyPull = "pull"
+	// DriftPolicyFlag records the drift on the mood for the user to resolve
+	DriftPolicyFlag = "flag"
+)
+
+// driftGracePeriod after a mood change, during which its playlist may still be catching up through the queue
+const driftGracePeriod = 10 * time.Minute
+
+// PlaylistDrift between a mood and its spotify playlist
+type PlaylistDrift struct {
+	DetectedAt *time.Time `json:"detected_at"`
+	// Name of the playlist, when it differs from the mood
+	Name *string `json:"name"`
+	// Description of the playlist, when the mood has one and it differs from the rendered one
+	Description *string `json:"description"`
+	// Unfollowed when the user no longer follows the playlist
+	Unfollowed bool `json:"unfollowed"`
+}
+
+// IsSet checks whether there is any drift
+func (d PlaylistDrift) IsSet() bool {
+	return d.Name != nil || d.Description != nil || d.Unfollowed
+}
+
+// ReconcileReport of a reconcile run over the moods of a user
+type ReconcileReport struct {
+	Checked int
+	Drifted int
+	// Failed moods could not be reconciled and are checked again on the next run
+	Failed int
+}
+
+// ReconcilePlaylists of every mood of the user against spotify, resolving drift by the user's policy.
+// A mood which fails is logged and skipped, so that it cannot hold up the others.
+// An error is only returned when the moods cannot be loaded, or when every checked mood failed.
+func (s *MoodService) ReconcilePlaylists(token *SpotifyToken) (*ReconcileReport, error) {
+	moods, err := s.r.FindByUser(&token.User)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ReconcileReport{}
+	var lastErr error
+	for _, mood := range moods {
+		if mood.PlaylistID == "" || time.Since(mood.UpdatedAt) < driftGracePeriod {
+			continue
+		}
+
+		report.Checked++
+		drifted, err := s.reconcileMood(mood, token)
+		if err != nil {
+			log.Printf("Failed to reconcile playlist of mood (ID: %d): %v", mood.ID, err)
+			report.Failed++
+			lastErr = err
+			continue
+		}
+		if drifted {
+			report.Drifted++
+		}
+	}
+
+	if report.Failed > 0 && report.Failed == report.Checked {
+		return report, lastErr
+	}
+	return report, nil
+}
+
+// reconcileMood against its playlist in spotify, telling whether it drifted
+func (s *MoodService) reconcileMood(mood *Mood, token *SpotifyToken) (bool, error) {
+	drift, err := s.detectDrift(mood, token)
+	if err != nil {
+		return false, err
+	}
+	if !drift.IsSet() {
+		// Drift resolved directly in spotify is cleared as well
+		if mood.Drift.IsSet() {
+			mood.Drift = PlaylistDrift{}
+			return false, s.r.Update(mood, "Drift")
+		}
+		return false, nil
+	}
+
+	// Drift which was already flagged keeps the time it was first detected at
+	if mood.Drift.DetectedAt != nil {
+		drift.DetectedAt = mood.Drift.DetectedAt
+	}
+
+	log.Printf("Playlist drift found for mood (ID: %d): %+v", mood.ID, drift)
+	return true, s.resolveDrift(mood, drift, token.User.DriftPolicy, token, AuditSourceScheduler, nil)
+}
+
+// ResolveDrift recorded on the given mood by the given policy
+func (s *MoodService) ResolveDrift(mood *Mood, policy string, token *SpotifyToken) error {
+	if !mood.Drift.IsSet() {
+		return nil
+	}
+
+	return s.resolveDrift(mood, mood.Drift, policy, token, AuditSourceAPI, &token.User)
+}
+
+// detectDrift compares the given mood with its playlist in spotify
+func (s *MoodService) detectDrift(mood *Mood, token *SpotifyToken) (PlaylistDrift, error) {
+	var drift PlaylistDrift
+
+	playlist, err := s.spotify.GetPlaylist(token, mood.PlaylistID)
+	if err != nil {
+		return drift, err
+	}
+	if playlist.Name != mood.Name {
+		drift.Name = &playlist.Name
+	}
+
+	// Descriptions are only compared for moods which manage one, spotify responds with them html escaped
+	if mood.Description != "" {
+		description, err := s.renderDescription(mood, token)
+		if err != nil {
+			return drift, err
+		}
+		if current := html.UnescapeString(playlist.Description); current != description {
+			drift.Description = &current
+		}
+	}
+
+	following, err := s.spotify.IsFollowingPlaylist(token, mood.PlaylistID)
+	if err != nil {
+		return drift, err
+	}
+	drift.Unfollowed = !following
+
+	if drift.IsSet() {
+		now := time.Now()
+		drift.DetectedAt = &now
+	}
+
+	return drift, nil
+}
+
+// resolveDrift of the given mood by the given policy.
+// An unfollowed playlist is never pulled in, as that would mean deleting the mood, so it stays flagged instead.
+// A name pulled in is audited as coming from the given source and actor.
+func (s *MoodService) resolveDrift(mood *Mood, drift PlaylistDrift, policy string, token *SpotifyToken, source string, actor *User) error {
+	before := mood.auditState()
+	cover := mood.coverOptions()
+
+	switch policy {
+	case DriftPolicyPush:
+		if drift.Unfollowed {
+			if err := s.spotify.FollowPlaylist(token, mood.PlaylistID); err != nil {
+				return err
+			}
+		}
+		if drift.Name != nil || drift.Description != nil {
+			details, err := s.playlistDetails(mood, token)
+			if err != nil {
+				return err
+			}
+			if err := s.spotify.UpdatePlaylist(token, mood.PlaylistID, details); err != nil {
+				return err
+			}
+		}
+		drift = PlaylistDrift{}
+	case DriftPolicyPull:
+		pulled := drift.Name != nil || drift.Description != nil
+		if drift.Name != nil {
+			mood.Name = *drift.Name
+			drift.Name = nil
+		}
+		// A template is never replaced by text it was rendered into, so only plain descriptions are pulled in
+		if drift.Description != nil {
+			if !hasPlaceholders(mood.Description) {
+				mood.Description = *drift.Description
+			}
+			drift.Description = nil
+		}
+
+		// The description is pushed again, as it may refer to the pulled name or still be rendered from the template
+		if pulled && mood.Description != "" {
+			details, err := s.playlistDetails(mood, token)
+			if err != nil {
+				return err
+			}
+			if err := s.spotify.UpdatePlaylist(token, mood.PlaylistID, details); err != nil {
+				return err
+			}
+		}
+		if !drift.IsSet() {
+			drift = PlaylistDrift{}
+		}
+	}
+
+	mood.Drift = drift
+	if err := s.r.Update(mood, "Name", "Description", "Drift"); err != nil {
+		return err
+	}
+
+	// A pulled name is drawn on the cover, which is rendered again then
+	if mood.coverOptions() != cover {
+		if err := s.q.UploadCover(mood); err != nil {
+			return err
+		}
+	}
+
+	if diff := diffAudit(before, mood.auditState()); diff.Name != nil {
+		s.record(mood, AuditActionUpdate, source, actor, diff)
+	}
+	return nil
+}
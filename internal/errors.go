@@ -6,4 +6,10 @@ import "errors"
 var (
 	ErrNotFound     = errors.New("not found")
 	ErrTokenExpired = errors.New("token expired")
+	ErrNoPlaylist   = errors.New("mood has no playlist")
+	ErrNotOwner     = errors.New("playlist is not owned by the user")
+	ErrAdopted      = errors.New("playlist already belongs to a mood")
+
+	ErrPublicCollaborative = errors.New("collaborative playlists cannot be public")
+	ErrInvalidOrder        = errors.New("order must list every mood exactly once")
 )
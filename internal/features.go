@@ -0,0 +1,60 @@
+package internal
+
+// FeatureRange bounds an audio feature, where either end may be left open
+type FeatureRange struct {
+	Min *float64 `json:"min"`
+	Max *float64 `json:"max"`
+}
+
+// IsSet checks whether the range is bounded on any end
+func (r FeatureRange) IsSet() bool {
+	return r.Min != nil || r.Max != nil
+}
+
+// Contains checks whether the given value falls within the range
+func (r FeatureRange) Contains(value float64) bool {
+	return (r.Min == nil || value >= *r.Min) && (r.Max == nil || value <= *r.Max)
+}
+
+// MoodFeatures are the ranges of audio features that tracks of a mood fall into
+//
+// Docs: https://developer.spotify.com/documentation/web-api/reference/tracks/get-audio-features/
+type MoodFeatures struct {
+	Energy       FeatureRange `gorm:"embedded;embedded_prefix:energy_" json:"energy"`
+	Valence      FeatureRange `gorm:"embedded;embedded_prefix:valence_" json:"valence"`
+	Danceability FeatureRange `gorm:"embedded;embedded_prefix:danceability_" json:"danceability"`
+	Acousticness FeatureRange `gorm:"embedded;embedded_prefix:acousticness_" json:"acousticness"`
+	Tempo        FeatureRange `gorm:"embedded;embedded_prefix:tempo_" json:"tempo"`
+}
+
+// IsSet checks whether any of the feature ranges is bounded
+func (f MoodFeatures) IsSet() bool {
+	return f.Energy.IsSet() || f.Valence.IsSet() || f.Danceability.IsSet() || f.Acousticness.IsSet() || f.Tempo.IsSet()
+}
+
+// Match counts how many of the set feature ranges contain the given track features, out of all set ranges
+func (f MoodFeatures) Match(features *SpotifyAudioFeatures) (matched, total int) {
+	checks := []struct {
+		r     FeatureRange
+		value float64
+	}{
+		{f.Energy, features.Energy},
+		{f.Valence, features.Valence},
+		{f.Danceability, features.Danceability},
+		{f.Acousticness, features.Acousticness},
+		{f.Tempo, features.Tempo},
+	}
+
+	for _, check := range checks {
+		if !check.r.IsSet() {
+			continue
+		}
+
+		total++
+		if check.r.Contains(check.value) {
+			matched++
+		}
+	}
+
+	return matched, total
+}
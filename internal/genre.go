@@ -0,0 +1,144 @@
+package internal
+
+import (
+	"fmt"
+	"sort"
+)
+
+// maxGenreSeeds is the amount of seeds spotify accepts for a single recommendations request
+const maxGenreSeeds = 5
+
+// Genre available for tagging moods with
+type Genre struct {
+	Name string `json:"name"`
+	// Seed marks genres spotify can generate recommendations for
+	Seed bool `json:"seed"`
+	// Tagged marks genres of artists the user tagged their moods with
+	Tagged bool `json:"tagged"`
+}
+
+// GenreCount of how many artists belong to a genre
+type GenreCount struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// GetGenres lists spotify's genre seeds along with the genres of every artist tagged in the user's moods
+func (s *MoodService) GetGenres(token *SpotifyToken) ([]Genre, error) {
+	seeds, err := s.spotify.GetGenreSeeds(token)
+	if err != nil {
+		return nil, err
+	}
+
+	moods, err := s.r.FindByUser(&token.User)
+	if err != nil {
+		return nil, err
+	}
+
+	artistIDs := make([]string, 0)
+	for _, mood := range moods {
+		artistIDs = append(artistIDs, IDsByType(mood.Tags, TagTypeArtist)...)
+	}
+
+	artists, _, err := s.spotify.GetArtistsByIDs(token, artistIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	genres := make(map[string]*Genre)
+	for _, seed := range seeds {
+		genres[seed] = &Genre{Name: seed, Seed: true}
+	}
+	for _, artist := range artists {
+		for _, name := range artist.Genres {
+			if _, ok := genres[name]; !ok {
+				genres[name] = &Genre{Name: name}
+			}
+			genres[name].Tagged = true
+		}
+	}
+
+	list := make([]Genre, 0, len(genres))
+	for _, genre := range genres {
+		list = append(list, *genre)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+
+	return list, nil
+}
+
+// countGenres of the given artists, ordered from the most common genre
+func countGenres(artists map[string]*SpotifyArtist) []GenreCount {
+	counts := make(map[string]int)
+	for _, artist := range artists {
+		for _, genre := range artist.Genres {
+			counts[genre]++
+		}
+	}
+
+	list := make([]GenreCount, 0, len(counts))
+	for name, count := range counts {
+		list = append(list, GenreCount{Name: name, Count: count})
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Count != list[j].Count {
+			return list[i].Count > list[j].Count
+		}
+		return list[i].Name < list[j].Name
+	})
+
+	return list
+}
+
+// genreSources gathers candidate tracks for the given genres.
+// Genres spotify has seeds for are used for recommendations, while any others are searched for.
+func (s *MoodService) genreSources(genres []string, token *SpotifyToken) ([][]*SpotifyTrack, error) {
+	sources := make([][]*SpotifyTrack, 0)
+	if len(genres) == 0 {
+		return sources, nil
+	}
+
+	seeds, err := s.spotify.GetGenreSeeds(token)
+	if err != nil {
+		return nil, err
+	}
+
+	seeded := make([]string, 0)
+	for _, genre := range genres {
+		if !containsString(seeds, genre) {
+			page, err := s.spotify.SearchForTracks(token, fmt.Sprintf(`genre:"%s"`, genre), SpotifyPageOptions{Limit: 50})
+			if err != nil {
+				return nil, err
+			}
+			sources = append(sources, page.Items)
+			continue
+		}
+		seeded = append(seeded, genre)
+	}
+
+	for start := 0; start < len(seeded); start += maxGenreSeeds {
+		end := start + maxGenreSeeds
+		if end > len(seeded) {
+			end = len(seeded)
+		}
+
+		tracks, err := s.spotify.GetRecommendations(token, SpotifyRecommendationSeeds{Genres: seeded[start:end]}, maxGeneratedTracks)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, tracks)
+	}
+
+	return sources, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
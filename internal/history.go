@@ -0,0 +1,195 @@
+package internal
+
+import (
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxRecentlyPlayedPage is the amount of recently played tracks spotify returns in a single page
+const maxRecentlyPlayedPage = 50
+
+// Play of a track by a user, attributed to the moods whose tags matched it
+type Play struct {
+	ID     uint `gorm:"primary_key" json:"id"`
+	UserID uint `gorm:"not null;unique_index:idx_plays_user_played_at" json:"-"`
+	// PlayedAt is kept to the millisecond, which is the precision spotify reports plays with
+	PlayedAt    time.Time `gorm:"type:datetime(3);not null;unique_index:idx_plays_user_played_at" json:"played_at"`
+	TrackID     string    `gorm:"not null;index" json:"track_id"`
+	TrackName   string    `json:"track_name"`
+	ArtistNames string    `gorm:"size:500" json:"artist_names"`
+	DurationMs  int       `json:"duration_ms"`
+	Moods       []*Mood   `gorm:"many2many:play_moods;association_autoupdate:false;association_autocreate:false" json:"moods"`
+}
+
+// PlayCount of a mood within a single day
+type PlayCount struct {
+	Date  string `json:"date"`
+	Count int    `json:"count"`
+}
+
+// PlayRepository for interacting with listening history data
+type PlayRepository interface {
+	// FindByUser lists plays of the given user played before the given time, from the latest
+	FindByUser(user *User, before time.Time, limit int) ([]*Play, error)
+	// LatestPlayedAt of the given user, zero when nothing was stored yet
+	LatestPlayedAt(user *User) (time.Time, error)
+	// SaveAll stores the given plays along with their moods, skipping plays which are already stored.
+	// Returns the amount of plays actually stored.
+	SaveAll(plays []*Play) (int, error)
+	// CountByMood counts the plays attributed to the given mood per day since the given time
+	CountByMood(mood *Mood, since time.Time) ([]PlayCount, error)
+	// CountTotalByMoods counts all plays attributed to each of the given moods
+	CountTotalByMoods(moodIDs []uint) (map[uint]int, error)
+}
+
+// HistoryService for ingesting and querying listening history
+type HistoryService struct {
+	r       PlayRepository
+	moods   MoodRepository
+	spotify SpotifyClient
+}
+
+// NewHistoryService constructor
+func NewHistoryService(r PlayRepository, moods MoodRepository, s SpotifyClient) *HistoryService {
+	return &HistoryService{
+		r:       r,
+		moods:   moods,
+		spotify: s,
+	}
+}
+
+// GetHistory of the given user, played before the given time
+func (s *HistoryService) GetHistory(user *User, before time.Time, limit int) ([]*Play, error) {
+	if before.IsZero() {
+		before = time.Now()
+	}
+	return s.r.FindByUser(user, before, limit)
+}
+
+// GetPlayCounts per day of the given mood over the given amount of days
+func (s *HistoryService) GetPlayCounts(mood *Mood, days int) ([]PlayCount, error) {
+	since := time.Now().AddDate(0, 0, -days).Truncate(24 * time.Hour)
+	return s.r.CountByMood(mood, since)
+}
+
+// SyncRecentlyPlayed stores the tracks the user played since the last sync and attributes them to their moods.
+// Returns the amount of newly stored plays.
+func (s *HistoryService) SyncRecentlyPlayed(token *SpotifyToken) (int, error) {
+	latest, err := s.r.LatestPlayedAt(&token.User)
+	if err != nil {
+		return 0, err
+	}
+
+	items, err := s.recentlyPlayedSince(token, latest)
+	if err != nil {
+		return 0, err
+	}
+	if len(items) == 0 {
+		return 0, nil
+	}
+
+	moods, err := s.moods.FindByUser(&token.User)
+	if err != nil {
+		return 0, err
+	}
+
+	artists, err := s.playedArtists(token, items)
+	if err != nil {
+		return 0, err
+	}
+
+	plays := make([]*Play, 0, len(items))
+	for _, item := range items {
+		plays = append(plays, newPlay(token.UserID, item, moods, artists))
+	}
+
+	return s.r.SaveAll(plays)
+}
+
+// recentlyPlayedSince retrieves every track the user played after the given time,
+// following the after cursor of spotify until there is nothing newer
+func (s *HistoryService) recentlyPlayedSince(token *SpotifyToken, since time.Time) ([]*SpotifyPlayHistory, error) {
+	items := make([]*SpotifyPlayHistory, 0)
+	opts := SpotifyCursorOptions{Limit: maxRecentlyPlayedPage, After: since}
+	for {
+		recent, err := s.spotify.GetRecentlyPlayed(token, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		// The cursor is only precise to the millisecond, so anything up to it is already stored
+		for _, item := range recent.Items {
+			item.PlayedAt = item.PlayedAt.Truncate(time.Millisecond)
+			if item.PlayedAt.After(since) {
+				items = append(items, item)
+			}
+		}
+
+		if recent.Next == "" || recent.Cursors.After == "" || len(recent.Items) == 0 {
+			return items, nil
+		}
+		millis, err := strconv.ParseInt(recent.Cursors.After, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		// A cursor which does not move forward would request the same page again
+		after := time.Unix(0, millis*int64(time.Millisecond))
+		if !after.After(opts.After) {
+			return items, nil
+		}
+		opts.After = after
+	}
+}
+
+// playedArtists retrieves every artist of the given plays, which are needed for their genres
+func (s *HistoryService) playedArtists(token *SpotifyToken, items []*SpotifyPlayHistory) (map[string]*SpotifyArtist, error) {
+	ids := make([]string, 0)
+	for _, item := range items {
+		for _, artist := range item.Track.Artists {
+			ids = append(ids, artist.ID)
+		}
+	}
+
+	artists, missing, err := s.spotify.GetArtistsByIDs(token, ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(missing) > 0 {
+		log.Printf("Played artists not found in spotify: %v", missing)
+	}
+
+	return artists, nil
+}
+
+// newPlay of the given history item, linked to every mood whose tags match the played track
+func newPlay(userID uint, item *SpotifyPlayHistory, moods []*Mood, artists map[string]*SpotifyArtist) *Play {
+	track := &item.Track
+	play := &Play{
+		UserID:     userID,
+		PlayedAt:   item.PlayedAt,
+		TrackID:    track.ID,
+		TrackName:  track.Name,
+		DurationMs: track.DurationMs,
+		Moods:      make([]*Mood, 0),
+	}
+
+	names := make([]string, 0, len(track.Artists))
+	trackArtists := make([]*SpotifyArtist, 0, len(track.Artists))
+	for _, artist := range track.Artists {
+		names = append(names, artist.Name)
+		if full, ok := artists[artist.ID]; ok {
+			trackArtists = append(trackArtists, full)
+		}
+	}
+	play.ArtistNames = strings.Join(names, ", ")
+
+	for _, mood := range moods {
+		if matchMood(mood, track, trackArtists, nil).Score > 0 {
+			play.Moods = append(play.Moods, mood)
+		}
+	}
+
+	return play
+}
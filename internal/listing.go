@@ -0,0 +1,83 @@
+package internal
+
+import "time"
+
+// Sort orders of mood listings
+const (
+	MoodSortPosition  = "position"
+	MoodSortName      = "name"
+	MoodSortCreatedAt = "created_at"
+	MoodSortUpdatedAt = "updated_at"
+)
+
+// MoodQuery for listing a page of a user's moods
+type MoodQuery struct {
+	// Sort by one of the mood sort orders, with the mood ID breaking ties.
+	// Sorting by position lists pinned moods first.
+	Sort string
+	Desc bool
+	// After the mood the cursor points at, to continue from the previous page
+	After *MoodCursor
+	Limit int
+
+	// HasPlaylist keeps either only the moods with a playlist or only those without one, when set
+	HasPlaylist *bool
+	Color       string
+	// Name substring of the moods to keep
+	Name string
+	// ArtistID of a tagged artist the moods to keep must contain
+	ArtistID string
+}
+
+// MoodCursor pointing at the last mood of a listed page, by the sort order it was listed in
+type MoodCursor struct {
+	Sort     string    `json:"sort"`
+	Desc     bool      `json:"desc,omitempty"`
+	Pinned   bool      `json:"pinned,omitempty"`
+	Position int       `json:"position,omitempty"`
+	Name     string    `json:"name,omitempty"`
+	Time     time.Time `json:"time,omitempty"`
+	ID       uint      `json:"id"`
+}
+
+// cursor pointing at the mood in the sort order of the given query
+func (m *Mood) cursor(query MoodQuery) *MoodCursor {
+	cursor := &MoodCursor{Sort: query.Sort, Desc: query.Desc, ID: m.ID}
+	switch query.Sort {
+	case MoodSortPosition:
+		cursor.Pinned = m.Pinned
+		cursor.Position = m.Position
+	case MoodSortName:
+		cursor.Name = m.Name
+	case MoodSortCreatedAt:
+		cursor.Time = m.CreatedAt
+	case MoodSortUpdatedAt:
+		cursor.Time = m.UpdatedAt
+	}
+
+	return cursor
+}
+
+// GetMoods lists a page of the given user's moods by the given query,
+// along with the cursor of the following page if there is one
+func (s *MoodService) GetMoods(user *User, query MoodQuery) ([]*Mood, *MoodCursor, error) {
+	// One more mood than requested tells whether there is a following page
+	limit := query.Limit
+	query.Limit++
+
+	moods, err := s.r.FindPageByUser(user, query)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(moods) <= limit {
+		return moods, nil, nil
+	}
+
+	moods = moods[:limit]
+	return moods, moods[limit-1].cursor(query), nil
+}
+
+// ReorderForUser arranges all moods of the given user in the order of the given IDs
+func (s *MoodService) ReorderForUser(user *User, ids []uint) error {
+	return s.r.Reorder(user, ids)
+}
@@ -2,20 +2,58 @@ package internal
 
 import (
 	"encoding/json"
+	"log"
 	"time"
 )
 
 // Mood represents a Mood entity
 type Mood struct {
-	ID         uint      `gorm:"primary_key" json:"id"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
-	Name       string    `gorm:"not null" json:"name"`
-	Color      string    `gorm:"not null" json:"color"`
-	PlaylistID string    `json:"playlist_id"`
-	UserID     uint      `json:"-"`
-	User       User      `json:"-"`
-	Tags       []Tag     `json:"tags"`
+	ID         uint       `gorm:"primary_key" json:"id"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	Name       string     `gorm:"not null" json:"name"`
+	Color      string     `gorm:"not null" json:"color"`
+	PlaylistID string     `json:"playlist_id"`
+	DeletedAt  *time.Time `sql:"index" json:"-"`
+	UserID     uint       `json:"-"`
+	User       User       `json:"-"`
+	Tags       []Tag      `json:"tags"`
+
+	// Description template of the playlist, see the description placeholders
+	Description string `gorm:"size:300" json:"description"`
+
+	Features MoodFeatures `gorm:"embedded" json:"features"`
+
+	// Playlist visibility, where a collaborative playlist can never be public
+	Public        bool `json:"public"`
+	Collaborative bool `json:"collaborative"`
+
+	// CoverInitials draws the initials of the name large on the generated playlist cover
+	CoverInitials bool `json:"cover_initials"`
+
+	// Position of the mood on the user's dashboard, where pinned moods come before all others
+	Position int  `gorm:"not null;default:0" json:"position"`
+	Pinned   bool `gorm:"not null;default:false" json:"pinned"`
+
+	// Drift from the mood found in its spotify playlist, which is still to be resolved
+	Drift PlaylistDrift `gorm:"embedded;embedded_prefix:drift_" json:"drift"`
+
+	// PlaylistQueuedAt is the last time the playlist creation was queued
+	PlaylistQueuedAt *time.Time `json:"-"`
+	// PlaylistDeletedAt is the time the playlist of a deleted mood was unfollowed
+	PlaylistDeletedAt *time.Time `json:"-"`
+
+	// ShareToken publishes a read-only view of the mood when set
+	ShareToken *string    `gorm:"unique_index" json:"share_token"`
+	SharedAt   *time.Time `json:"shared_at"`
+
+	// Details of the last time the playlist tracks were generated
+	TrackCount int        `json:"track_count"`
+	DurationMs int        `json:"duration_ms"`
+	SyncedAt   *time.Time `json:"synced_at"`
+
+	// Genres of the tagged artists, only populated along with tag data
+	Genres []GenreCount `gorm:"-" json:"genres,omitempty"`
 }
 
 // MarshalJSON for api responses
@@ -29,48 +67,178 @@ func (m *Mood) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// PlaylistDetails of the mood's playlist
+func (m *Mood) PlaylistDetails() PlaylistDetails {
+	return PlaylistDetails{
+		Name:          m.Name,
+		Public:        &m.Public,
+		Collaborative: &m.Collaborative,
+	}
+}
+
+// MoodChanges to apply to a mood, where nil fields are left as they are
+type MoodChanges struct {
+	Name          *string
+	Color         *string
+	Description   *string
+	CoverInitials *bool
+	Public        *bool
+	Collaborative *bool
+	Features      *MoodFeatures
+	Pinned        *bool
+	// Tags replace all of the mood's tags when not nil
+	Tags []Tag
+}
+
+// apply every set field, apart from tags, onto the given mood
+func (c MoodChanges) apply(mood *Mood) {
+	if c.Name != nil {
+		mood.Name = *c.Name
+	}
+	if c.Color != nil {
+		mood.Color = *c.Color
+	}
+	if c.Description != nil {
+		mood.Description = *c.Description
+	}
+	if c.CoverInitials != nil {
+		mood.CoverInitials = *c.CoverInitials
+	}
+	if c.Public != nil {
+		mood.Public = *c.Public
+	}
+	if c.Collaborative != nil {
+		mood.Collaborative = *c.Collaborative
+	}
+	if c.Features != nil {
+		mood.Features = *c.Features
+	}
+	if c.Pinned != nil {
+		mood.Pinned = *c.Pinned
+	}
+}
+
+// fields set by the change set, by their names in the mood, apart from tags
+func (c MoodChanges) fields() []string {
+	var fields []string
+	set := []struct {
+		name string
+		ok   bool
+	}{
+		{"Name", c.Name != nil},
+		{"Color", c.Color != nil},
+		{"Description", c.Description != nil},
+		{"CoverInitials", c.CoverInitials != nil},
+		{"Public", c.Public != nil},
+		{"Collaborative", c.Collaborative != nil},
+		{"Features", c.Features != nil},
+		{"Pinned", c.Pinned != nil},
+	}
+	for _, field := range set {
+		if field.ok {
+			fields = append(fields, field.name)
+		}
+	}
+
+	return fields
+}
+
+// onlyTags tells whether the change set replaces the tags and nothing else
+func (c MoodChanges) onlyTags() bool {
+	return c.Tags != nil && c.Name == nil && c.Color == nil && c.Description == nil && c.CoverInitials == nil &&
+		c.Public == nil && c.Collaborative == nil && c.Features == nil && c.Pinned == nil
+}
+
 // MoodRepository for interacting with mood data
 type MoodRepository interface {
 	// Find mood by ID and User
 	Find(id uint) (*Mood, error)
 	// FindByIDAndUser if it exists
 	FindByIDAndUser(id uint, user *User) (*Mood, error)
-	// Remove mood by ID
+	// FindByIDAndUserWithDeleted if it exists, even when it was deleted
+	FindByIDAndUserWithDeleted(id uint, user *User) (*Mood, error)
+	// FindByPlaylistID the mood managing the given playlist if it exists, including a deleted mood which still has it
+	FindByPlaylistID(playlistID string) (*Mood, error)
+	// FindByShareToken a published mood if it exists
+	FindByShareToken(token string) (*Mood, error)
+	// Remove mood by ID, keeping it marked as deleted
 	Remove(id uint) error
+	// FindDeletedByUser moods of the given user deleted after the given time, from the latest
+	FindDeletedByUser(user *User, after time.Time) ([]*Mood, error)
+	// Restore the mood by ID and user, if it was deleted after the given time
+	Restore(id uint, user *User, after time.Time) (*Mood, error)
 	// FindByUser all moods for a given user
 	FindByUser(user *User) ([]*Mood, error)
+	// FindPageByUser a page of moods of the given user matching the given query
+	FindPageByUser(user *User, query MoodQuery) ([]*Mood, error)
+	// NextPosition after the last of the given user's moods
+	NextPosition(user *User) (int, error)
+	// Reorder the moods of the given user by the given IDs, which must list every one of their moods exactly once
+	Reorder(user *User, ids []uint) error
 	// Save upserts the given mood into the DB
 	Save(mood *Mood) error
-	// Update persists the given changeset to the given mood
-	Update(mood *Mood, changes Mood) error
+	// SaveAll inserts all of the given moods at once, or none of them on failure
+	SaveAll(moods []*Mood) error
+	// Update persists only the given fields of the mood, leaving all other columns as they are
+	Update(mood *Mood, fields ...string) error
+	// ReplaceTags of the given mood with the given set of tags
+	ReplaceTags(mood *Mood, tags []Tag) error
+	// UpdateSync persists the playlist sync details of the given mood
+	UpdateSync(mood *Mood) error
+	// FindWithoutPlaylist moods created and last queued for a playlist before the given time, which still have none
+	FindWithoutPlaylist(before time.Time) ([]*Mood, error)
+	// FindUndeletedPlaylists of moods deleted before the given time, whose playlist was never unfollowed
+	FindUndeletedPlaylists(before time.Time) ([]*Mood, error)
+	// MarkPlaylistQueued at the given time for the given mood
+	MarkPlaylistQueued(mood *Mood, at time.Time) error
+	// MarkPlaylistDeleted at the given time for the given, possibly deleted, mood
+	MarkPlaylistDeleted(id uint, at time.Time) error
+	// CountTags per tag type for each of the given moods
+	CountTags(moodIDs []uint) (map[uint]map[string]int, error)
 }
 
 // MoodService for performing all operations related to moods
 type MoodService struct {
-	r       MoodRepository
-	q       QueueService
-	spotify SpotifyClient
+	r         MoodRepository
+	blocks    BlockRepository
+	snapshots SnapshotRepository
+	audit     AuditRepository
+	q         QueueService
+	spotify   SpotifyClient
 }
 
 // NewMoodService constructor
-func NewMoodService(r MoodRepository, q QueueService, s SpotifyClient) *MoodService {
+func NewMoodService(r MoodRepository, blocks BlockRepository, snapshots SnapshotRepository, audit AuditRepository, q QueueService, s SpotifyClient) *MoodService {
 	return &MoodService{
-		r:       r,
-		q:       q,
-		spotify: s,
+		r:         r,
+		blocks:    blocks,
+		snapshots: snapshots,
+		audit:     audit,
+		q:         q,
+		spotify:   s,
 	}
 }
 
 // AddMood for the given user
-func (s *MoodService) AddMood(name, color string, user *User) (*Mood, error) {
-	mood := &Mood{
-		Name:  name,
-		Color: color,
-		User:  *user,
+func (s *MoodService) AddMood(mood *Mood, user *User) (*Mood, error) {
+	if mood.Collaborative && mood.Public {
+		return nil, ErrPublicCollaborative
+	}
+
+	// New moods are appended at the end of the user's moods
+	position, err := s.r.NextPosition(user)
+	if err != nil {
+		return nil, err
 	}
+
+	now := time.Now()
+	mood.User = *user
+	mood.Position = position
+	mood.PlaylistQueuedAt = &now
 	if err := s.r.Save(mood); err != nil {
 		return nil, err
 	}
+	s.record(mood, AuditActionCreate, AuditSourceAPI, user, diffAudit(auditState{}, mood.auditState()))
 
 	// Add task to create playlist in spotify
 	if err := s.q.AddPlaylist(mood); err != nil {
@@ -81,27 +249,57 @@ func (s *MoodService) AddMood(name, color string, user *User) (*Mood, error) {
 }
 
 // UpdateMoodForUser for a given change set
-func (s *MoodService) UpdateMoodForUser(id uint, changes Mood, user *User) (*Mood, error) {
+func (s *MoodService) UpdateMoodForUser(id uint, changes MoodChanges, user *User) (*Mood, error) {
 	mood, err := s.FindForUser(id, user)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := s.r.Update(mood, changes); err != nil {
+	// The cover only needs to be rendered again when anything drawn on it changed
+	cover := mood.coverOptions()
+	before := mood.auditState()
+	description := mood.Description
+
+	changes.apply(mood)
+	if mood.Collaborative && mood.Public {
+		return nil, ErrPublicCollaborative
+	}
+	if err := s.r.Update(mood, changes.fields()...); err != nil {
 		return nil, err
 	}
 
-	// Add task to update playlist in spotify
-	if err := s.q.UpdatePlaylist(mood); err != nil {
+	// Add task to update playlist in spotify, where a removed description is cleared as well
+	if err := s.q.UpdatePlaylist(mood, description != "" && mood.Description == ""); err != nil {
 		return nil, err
 	}
+	if mood.PlaylistID != "" && mood.coverOptions() != cover {
+		if err := s.q.UploadCover(mood); err != nil {
+			return nil, err
+		}
+	}
 
-	return mood, nil
-}
+	// Tags are replaced as a whole, only when a new set of tags was given
+	if changes.Tags != nil {
+		if err := s.r.ReplaceTags(mood, changes.Tags); err != nil {
+			return nil, err
+		}
+
+		// Add task to regenerate the playlist tracks, unless the playlist is still to be created
+		if mood.PlaylistID != "" {
+			if err := s.q.RefreshPlaylist(mood); err != nil {
+				return nil, err
+			}
+		}
+	}
 
-// GetMoods finds all moods for a given user
-func (s *MoodService) GetMoods(user *User) ([]*Mood, error) {
-	return s.r.FindByUser(user)
+	diff := diffAudit(before, mood.auditState())
+	action := AuditActionUpdate
+	if changes.onlyTags() {
+		action = AuditActionTags
+	}
+	s.record(mood, action, AuditSourceAPI, user, diff)
+
+	return mood, nil
 }
 
 // FindForUser finds a mood by the given ID and user
@@ -109,6 +307,11 @@ func (s *MoodService) FindForUser(id uint, user *User) (*Mood, error) {
 	return s.r.FindByIDAndUser(id, user)
 }
 
+// FindWithTrashForUser finds a mood by the given ID and user, including moods which were deleted
+func (s *MoodService) FindWithTrashForUser(id uint, user *User) (*Mood, error) {
+	return s.r.FindByIDAndUserWithDeleted(id, user)
+}
+
 // Find finds a mood by the given ID
 func (s *MoodService) Find(id uint) (*Mood, error) {
 	mood, err := s.r.Find(id)
@@ -118,7 +321,42 @@ func (s *MoodService) Find(id uint) (*Mood, error) {
 	return mood, nil
 }
 
-// DeleteForUser removes the stored mood by the given ID and user
+// PopulateTags fills in the spotify data of every tag of the given mood
+func (s *MoodService) PopulateTags(mood *Mood, token *SpotifyToken) error {
+	artists, missingArtists, err := s.spotify.GetArtistsByIDs(token, IDsByType(mood.Tags, TagTypeArtist))
+	if err != nil {
+		return err
+	}
+	tracks, missingTracks, err := s.spotify.GetTracksByIDs(token, IDsByType(mood.Tags, TagTypeTrack))
+	if err != nil {
+		return err
+	}
+	albums, missingAlbums, err := s.spotify.GetAlbumsByIDs(token, IDsByType(mood.Tags, TagTypeAlbum))
+	if err != nil {
+		return err
+	}
+
+	if missing := append(append(missingArtists, missingTracks...), missingAlbums...); len(missing) > 0 {
+		log.Printf("Tagged items not found in spotify for mood (ID: %d): %v", mood.ID, missing)
+	}
+
+	mood.Genres = countGenres(artists)
+	for i, tag := range mood.Tags {
+		switch tag.Type {
+		case TagTypeArtist:
+			mood.Tags[i].ArtistData = artists[tag.SpotifyID]
+		case TagTypeTrack:
+			mood.Tags[i].TrackData = tracks[tag.SpotifyID]
+		case TagTypeAlbum:
+			mood.Tags[i].AlbumData = albums[tag.SpotifyID]
+		}
+	}
+
+	return nil
+}
+
+// DeleteForUser moves the stored mood by the given ID and user to the trash.
+// Its playlist is left in place, until the sweep deletes it once the undo window has passed.
 func (s *MoodService) DeleteForUser(id uint, user *User) error {
 	mood, err := s.FindForUser(id, user)
 	if err != nil {
@@ -129,26 +367,40 @@ func (s *MoodService) DeleteForUser(id uint, user *User) error {
 		return err
 	}
 
-	// Add task to delete playlist in spotify if mood has playlist
-	if mood.PlaylistID != "" {
-		return s.q.DeletePlaylist(user.ID, mood.PlaylistID)
-	}
+	s.record(mood, AuditActionDelete, AuditSourceAPI, user, diffAudit(mood.auditState(), auditState{}))
 	return nil
 }
 
-// CreatePlaylistForMood adds a new playlist in spotify for the given mood id
-func (s *MoodService) CreatePlaylistForMood(name string, moodID uint, token *SpotifyToken) error {
+// CreatePlaylistForMood adds a new playlist in spotify for the given mood id.
+// Its tracks are generated by a job of their own, so that a failure there cannot fail the created playlist.
+func (s *MoodService) CreatePlaylistForMood(moodID uint, token *SpotifyToken) error {
 	mood, err := s.Find(moodID)
 	if err != nil {
 		return err
 	}
+	// Creation may be queued again while a previous job is still pending, which must not lead to a second playlist
+	if mood.PlaylistID != "" {
+		return nil
+	}
+
+	details, err := s.playlistDetails(mood, token)
+	if err != nil {
+		return err
+	}
 
-	id, err := s.spotify.CreatePlaylist(token, name)
+	id, err := s.spotify.CreatePlaylist(token, details)
 	if err != nil {
 		return err
 	}
 
 	mood.PlaylistID = id
+	if err := s.r.Update(mood, "PlaylistID"); err != nil {
+		return err
+	}
 
-	return s.r.Save(mood)
+	// Add tasks to generate the tracks and replace the default mosaic cover of the new playlist
+	if err := s.q.RefreshPlaylist(mood); err != nil {
+		return err
+	}
+	return s.q.UploadCover(mood)
 }
@@ -0,0 +1,159 @@
+package internal
+
+import (
+	"math"
+	"sort"
+)
+
+// Weights of every way a playing track can match a mood, where the final score is capped at 1
+const (
+	trackMatchWeight    = 1.0
+	albumMatchWeight    = 0.7
+	artistMatchWeight   = 0.6
+	genreMatchWeight    = 0.4
+	featuresMatchWeight = 0.4
+)
+
+// Reasons for a playing track matching a mood
+const (
+	MatchReasonTrack    = "track"
+	MatchReasonAlbum    = "album"
+	MatchReasonArtist   = "artist"
+	MatchReasonGenre    = "genre"
+	MatchReasonFeatures = "features"
+)
+
+// NowPlaying track of the user along with the moods it matches
+type NowPlaying struct {
+	IsPlaying bool                  `json:"is_playing"`
+	Track     *SpotifyTrack         `json:"track"`
+	Artists   []*SpotifyArtist      `json:"artists"`
+	Features  *SpotifyAudioFeatures `json:"features,omitempty"`
+	// Matches ordered from the best matching mood
+	Matches []*MoodMatch `json:"matches"`
+}
+
+// MoodMatch of the playing track with one of the user's moods
+type MoodMatch struct {
+	Mood    *Mood    `json:"mood"`
+	Score   float64  `json:"score"`
+	Reasons []string `json:"reasons"`
+}
+
+// GetNowPlaying finds the currently playing track of the user and scores how well it fits each of their moods.
+// Returns nil when nothing is playing.
+func (s *MoodService) GetNowPlaying(token *SpotifyToken) (*NowPlaying, error) {
+	current, err := s.spotify.GetCurrentlyPlaying(token)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil || current.Item == nil {
+		return nil, nil
+	}
+
+	// Local files have neither a track ID nor artist IDs, so there is nothing to look up for them
+	track := current.Item
+	artistIDs := make([]string, 0, len(track.Artists))
+	for _, artist := range track.Artists {
+		if artist.ID != "" {
+			artistIDs = append(artistIDs, artist.ID)
+		}
+	}
+
+	artistsByID, _, err := s.spotify.GetArtistsByIDs(token, artistIDs)
+	if err != nil {
+		return nil, err
+	}
+	artists := make([]*SpotifyArtist, 0, len(artistsByID))
+	for _, id := range artistIDs {
+		if artist, ok := artistsByID[id]; ok {
+			artists = append(artists, artist)
+		}
+	}
+
+	moods, err := s.r.FindByUser(&token.User)
+	if err != nil {
+		return nil, err
+	}
+
+	// Audio features are only needed when any mood defines feature ranges
+	var features *SpotifyAudioFeatures
+	for _, mood := range moods {
+		if track.ID != "" && mood.Features.IsSet() {
+			if features, err = s.spotify.GetAudioFeatures(token, track.ID); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+
+	matches := make([]*MoodMatch, 0)
+	for _, mood := range moods {
+		if match := matchMood(mood, track, artists, features); match.Score > 0 {
+			matches = append(matches, match)
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+
+	return &NowPlaying{
+		IsPlaying: current.IsPlaying,
+		Track:     track,
+		Artists:   artists,
+		Features:  features,
+		Matches:   matches,
+	}, nil
+}
+
+// matchMood scores how well the given track fits the given mood, from 0 for no match up to 1
+func matchMood(mood *Mood, track *SpotifyTrack, artists []*SpotifyArtist, features *SpotifyAudioFeatures) *MoodMatch {
+	match := &MoodMatch{Mood: mood, Reasons: make([]string, 0)}
+	score := 0.0
+
+	if containsString(IDsByType(mood.Tags, TagTypeTrack), track.ID) {
+		score += trackMatchWeight
+		match.Reasons = append(match.Reasons, MatchReasonTrack)
+	}
+
+	if track.Album != nil && containsString(IDsByType(mood.Tags, TagTypeAlbum), track.Album.ID) {
+		score += albumMatchWeight
+		match.Reasons = append(match.Reasons, MatchReasonAlbum)
+	}
+
+	taggedArtists := IDsByType(mood.Tags, TagTypeArtist)
+	for _, artist := range track.Artists {
+		if containsString(taggedArtists, artist.ID) {
+			score += artistMatchWeight
+			match.Reasons = append(match.Reasons, MatchReasonArtist)
+			break
+		}
+	}
+
+	taggedGenres := IDsByType(mood.Tags, TagTypeGenre)
+	if overlapsGenres(artists, taggedGenres) {
+		score += genreMatchWeight
+		match.Reasons = append(match.Reasons, MatchReasonGenre)
+	}
+
+	if features != nil {
+		if matched, total := mood.Features.Match(features); total > 0 && matched > 0 {
+			score += featuresMatchWeight * float64(matched) / float64(total)
+			match.Reasons = append(match.Reasons, MatchReasonFeatures)
+		}
+	}
+
+	match.Score = math.Round(math.Min(score, 1)*100) / 100
+	return match
+}
+
+func overlapsGenres(artists []*SpotifyArtist, genres []string) bool {
+	for _, artist := range artists {
+		for _, genre := range artist.Genres {
+			if containsString(genres, genre) {
+				return true
+			}
+		}
+	}
+	return false
+}
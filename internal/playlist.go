@@ -0,0 +1,193 @@
+package internal
+
+import "time"
+
+// maxGeneratedTracks caps how many tracks are picked for a mood playlist on top of its tagged tracks
+const maxGeneratedTracks = 100
+
+// RefreshPlaylistForMood regenerates the tracks of the playlist of the given mood id from its tags
+func (s *MoodService) RefreshPlaylistForMood(moodID uint, token *SpotifyToken) error {
+	mood, err := s.Find(moodID)
+	if err != nil {
+		return err
+	}
+	if mood.PlaylistID == "" {
+		return ErrNoPlaylist
+	}
+
+	// Keep the current version of the playlist, before it is replaced
+	if err := s.takeSnapshot(mood, token); err != nil {
+		return err
+	}
+
+	tracks, err := s.generateTracks(mood, token)
+	if err != nil {
+		return err
+	}
+
+	uris := make([]string, 0, len(tracks))
+	duration := 0
+	for _, track := range tracks {
+		uris = append(uris, track.URI)
+		duration += track.DurationMs
+	}
+
+	if err := s.spotify.SetPlaylistTracks(token, mood.PlaylistID, uris); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	mood.TrackCount = len(tracks)
+	mood.DurationMs = duration
+	mood.SyncedAt = &now
+
+	if err := s.r.UpdateSync(mood); err != nil {
+		return err
+	}
+	s.record(mood, AuditActionSync, AuditSourceWorker, nil, AuditChanges{})
+
+	// The description is rendered again on every sync, as it may refer to the refresh date
+	if mood.Description == "" {
+		return nil
+	}
+	details, err := s.playlistDetails(mood, token)
+	if err != nil {
+		return err
+	}
+
+	return s.spotify.UpdatePlaylist(token, mood.PlaylistID, details)
+}
+
+// generateTracks picks the tracks for the playlist of the given mood.
+// Tagged tracks are always included and come first, followed by tracks from every other tag taken in turns.
+// Tracks blocked for the mood or the user are left out of every source.
+func (s *MoodService) generateTracks(mood *Mood, token *SpotifyToken) ([]*SpotifyTrack, error) {
+	blocks, err := s.blocks.FindForMood(mood)
+	if err != nil {
+		return nil, err
+	}
+	picked := newTrackList(newBlocklist(blocks))
+
+	trackIDs := IDsByType(mood.Tags, TagTypeTrack)
+	tagged, _, err := s.spotify.GetTracksByIDs(token, trackIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range trackIDs {
+		if track, ok := tagged[id]; ok {
+			picked.add(track)
+		}
+	}
+
+	sources, err := s.trackSources(mood, token)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := picked.len() + maxGeneratedTracks
+	for i := 0; picked.len() < limit; i++ {
+		remaining := false
+		for _, source := range sources {
+			if i >= len(source) {
+				continue
+			}
+			remaining = true
+
+			if picked.add(source[i]); picked.len() >= limit {
+				break
+			}
+		}
+
+		if !remaining {
+			break
+		}
+	}
+
+	return picked.tracks, nil
+}
+
+// trackSources gathers the candidate tracks of every non-track tag of the given mood
+func (s *MoodService) trackSources(mood *Mood, token *SpotifyToken) ([][]*SpotifyTrack, error) {
+	sources := make([][]*SpotifyTrack, 0)
+
+	for _, id := range IDsByType(mood.Tags, TagTypeArtist) {
+		tracks, err := s.spotify.GetArtistTopTracks(token, id)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, tracks)
+	}
+
+	for _, id := range IDsByType(mood.Tags, TagTypeAlbum) {
+		tracks, err := s.spotify.GetAlbumTracks(token, id)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, tracks)
+	}
+
+	genreSources, err := s.genreSources(IDsByType(mood.Tags, TagTypeGenre), token)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(sources, genreSources...), nil
+}
+
+// trackList keeps tracks in the order they were added, skipping duplicates and blocked tracks
+type trackList struct {
+	tracks  []*SpotifyTrack
+	seen    map[string]bool
+	blocked *blocklist
+}
+
+func newTrackList(blocked *blocklist) *trackList {
+	return &trackList{
+		tracks:  make([]*SpotifyTrack, 0),
+		seen:    make(map[string]bool),
+		blocked: blocked,
+	}
+}
+
+func (l *trackList) add(track *SpotifyTrack) {
+	if track == nil || track.URI == "" || l.seen[track.ID] || l.blocked.blocks(track) {
+		return
+	}
+
+	l.seen[track.ID] = true
+	l.tracks = append(l.tracks, track)
+}
+
+func (l *trackList) len() int {
+	return len(l.tracks)
+}
+
+// maxPlaylistItemsPage is the amount of playlist items spotify returns in a single page
+const maxPlaylistItemsPage = 100
+
+// GetPlaylistTracks retrieves every playable track currently in the playlist of the given mood
+func (s *MoodService) GetPlaylistTracks(mood *Mood, token *SpotifyToken) ([]*SpotifyTrack, error) {
+	if mood.PlaylistID == "" {
+		return nil, ErrNoPlaylist
+	}
+
+	tracks := make([]*SpotifyTrack, 0)
+	opts := SpotifyPageOptions{Limit: maxPlaylistItemsPage}
+	for {
+		page, err := s.spotify.GetPlaylistItems(token, mood.PlaylistID, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range page.Items {
+			if item.Track != nil && item.Track.URI != "" {
+				tracks = append(tracks, item.Track)
+			}
+		}
+
+		if !page.HasNext() || len(page.Items) == 0 {
+			return tracks, nil
+		}
+		opts.Offset += len(page.Items)
+	}
+}
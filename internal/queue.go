@@ -5,7 +5,17 @@ type QueueService interface {
 	// AddPlaylist publishes a new message to the add_playlist queue
 	AddPlaylist(mood *Mood) error
 	// UpdatePlaylist publishes a new message to the update_playlist queue
-	UpdatePlaylist(mood *Mood) error
+	UpdatePlaylist(mood *Mood, clearDescription bool) error
+	// UploadCover publishes a new message to the upload_cover queue
+	UploadCover(mood *Mood) error
+	// RestoreSnapshot publishes a new message to the restore_snapshot queue
+	RestoreSnapshot(mood *Mood, snapshot *Snapshot, actor *User) error
+	// RefreshPlaylist publishes a new message to the refresh_playlist queue
+	RefreshPlaylist(mood *Mood) error
+	// SyncHistory publishes a new message to the sync_history queue
+	SyncHistory(userID uint) error
+	// ReconcilePlaylists publishes a new message to the reconcile_playlists queue
+	ReconcilePlaylists(userID uint) error
 	// DeletePlaylist publishes a new message to the delete_playlist queue
-	DeletePlaylist(userID uint, playlistID string) error
+	DeletePlaylist(mood *Mood) error
 }
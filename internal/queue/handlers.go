@@ -37,7 +37,7 @@ func (h *Handler) handleAddPlaylist(d amqp.Delivery) error {
 		return err
 	}
 
-	if err := h.services.Mood().CreatePlaylistForMood(payload.Name, payload.MoodID, token); err != nil {
+	if err := h.services.Mood().CreatePlaylistForMood(payload.MoodID, token); err != nil {
 		return err
 	}
 
@@ -58,7 +58,23 @@ func (h *Handler) handleUpdatePlaylist(d amqp.Delivery) error {
 		return err
 	}
 
-	if err := h.services.Spotify().UpdatePlaylist(token, payload.PlaylistID, payload.Name); err != nil {
+	// The description template is rendered from the latest state of the mood,
+	// while messages published before they carried a mood only update the given details
+	if payload.MoodID != 0 {
+		err = h.services.Mood().UpdatePlaylistForMood(payload.MoodID, payload.ClearDescription, token)
+		if err == internal.ErrNoPlaylist {
+			log.Printf("Skipped updating playlist for Mood ID %d, which has no playlist yet", payload.MoodID)
+			return nil
+		}
+	} else {
+		details := internal.PlaylistDetails{
+			Name:          payload.Name,
+			Public:        payload.Public,
+			Collaborative: payload.Collaborative,
+		}
+		err = h.services.Spotify().UpdatePlaylist(token, payload.PlaylistID, details)
+	}
+	if err != nil {
 		return err
 	}
 
@@ -67,6 +83,69 @@ func (h *Handler) handleUpdatePlaylist(d amqp.Delivery) error {
 	return nil
 }
 
+func (h *Handler) handleRefreshPlaylist(d amqp.Delivery) error {
+	log.Printf("handling '%s': %s", refreshPlaylistQueue, d.Body)
+
+	var payload model.RefreshPlaylistPayload
+	if err := json.Unmarshal(d.Body, &payload); err != nil {
+		return err
+	}
+
+	token, err := h.services.User().FindTokenForUser(payload.UserID)
+	if err != nil {
+		return err
+	}
+
+	if err := h.services.Mood().RefreshPlaylistForMood(payload.MoodID, token); err != nil {
+		return err
+	}
+
+	log.Printf("Successfully refreshed playlist tracks for Mood ID %d", payload.MoodID)
+	return nil
+}
+
+func (h *Handler) handleUploadCover(d amqp.Delivery) error {
+	log.Printf("handling '%s': %s", uploadCoverQueue, d.Body)
+
+	var payload model.UploadCoverPayload
+	if err := json.Unmarshal(d.Body, &payload); err != nil {
+		return err
+	}
+
+	token, err := h.services.User().FindTokenForUser(payload.UserID)
+	if err != nil {
+		return err
+	}
+
+	if err := h.services.Mood().UploadCoverForMood(payload.MoodID, token); err != nil {
+		return err
+	}
+
+	log.Printf("Successfully uploaded playlist cover for Mood ID %d", payload.MoodID)
+	return nil
+}
+
+func (h *Handler) handleRestoreSnapshot(d amqp.Delivery) error {
+	log.Printf("handling '%s': %s", restoreSnapshotQueue, d.Body)
+
+	var payload model.RestoreSnapshotPayload
+	if err := json.Unmarshal(d.Body, &payload); err != nil {
+		return err
+	}
+
+	token, err := h.services.User().FindTokenForUser(payload.UserID)
+	if err != nil {
+		return err
+	}
+
+	if err := h.services.Mood().RestoreSnapshot(payload.MoodID, payload.SnapshotID, payload.ActorID, token); err != nil {
+		return err
+	}
+
+	log.Printf("Successfully restored snapshot ID %d for Mood ID %d", payload.SnapshotID, payload.MoodID)
+	return nil
+}
+
 func (h *Handler) handleDeletePlaylist(d amqp.Delivery) error {
 	log.Printf("handling '%s': %s", deletePlaylistQueue, d.Body)
 
@@ -84,6 +163,57 @@ func (h *Handler) handleDeletePlaylist(d amqp.Delivery) error {
 		return err
 	}
 
+	// Messages published before moods were kept on deletion carry no mood to mark
+	if payload.MoodID != 0 {
+		if err := h.services.Mood().MarkPlaylistDeleted(payload.MoodID); err != nil {
+			return err
+		}
+	}
+
 	log.Printf("Successfully deleted playlist: %s", payload.PlaylistID)
 	return nil
 }
+
+func (h *Handler) handleSyncHistory(d amqp.Delivery) error {
+	log.Printf("handling '%s': %s", syncHistoryQueue, d.Body)
+
+	var payload model.SyncHistoryPayload
+	if err := json.Unmarshal(d.Body, &payload); err != nil {
+		return err
+	}
+
+	token, err := h.services.User().FindTokenForUser(payload.UserID)
+	if err != nil {
+		return err
+	}
+
+	count, err := h.services.History().SyncRecentlyPlayed(token)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Successfully synced %d plays for User ID %d", count, payload.UserID)
+	return nil
+}
+
+func (h *Handler) handleReconcilePlaylists(d amqp.Delivery) error {
+	log.Printf("handling '%s': %s", reconcileQueue, d.Body)
+
+	var payload model.ReconcilePlaylistsPayload
+	if err := json.Unmarshal(d.Body, &payload); err != nil {
+		return err
+	}
+
+	token, err := h.services.User().FindTokenForUser(payload.UserID)
+	if err != nil {
+		return err
+	}
+
+	report, err := h.services.Mood().ReconcilePlaylists(token)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Reconciled playlists for User ID %d, %d checked, %d drifted, %d failed", payload.UserID, report.Checked, report.Drifted, report.Failed)
+	return nil
+}
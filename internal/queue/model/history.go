@@ -0,0 +1,6 @@
+package model
+
+// SyncHistoryPayload for queue messages
+type SyncHistoryPayload struct {
+	UserID uint `json:"user_ID"`
+}
@@ -10,12 +10,45 @@ type AddPlaylistPayload struct {
 // UpdatePlaylistPayload for queue messages
 type UpdatePlaylistPayload struct {
 	UserID     uint   `json:"user_ID"`
+	MoodID     uint   `json:"mood_id"`
 	PlaylistID string `json:"playlist_id"`
 	Name       string `json:"name"`
+	// Public and Collaborative are left as they are when missing from older messages
+	Public        *bool `json:"public,omitempty"`
+	Collaborative *bool `json:"collaborative,omitempty"`
+	// ClearDescription of the playlist, once the description of the mood was removed
+	ClearDescription bool `json:"clear_description,omitempty"`
+}
+
+// RefreshPlaylistPayload for queue messages
+type RefreshPlaylistPayload struct {
+	UserID uint `json:"user_ID"`
+	MoodID uint `json:"mood_id"`
+}
+
+// UploadCoverPayload for queue messages
+type UploadCoverPayload struct {
+	UserID uint `json:"user_ID"`
+	MoodID uint `json:"mood_id"`
+}
+
+// RestoreSnapshotPayload for queue messages
+type RestoreSnapshotPayload struct {
+	UserID     uint `json:"user_ID"`
+	MoodID     uint `json:"mood_id"`
+	SnapshotID uint `json:"snapshot_id"`
+	// ActorID of the user who requested the restore
+	ActorID uint `json:"actor_id"`
 }
 
 // DeletePlaylistPayload for queue messages
 type DeletePlaylistPayload struct {
 	UserID     uint   `json:"user_ID"`
+	MoodID     uint   `json:"mood_id"`
 	PlaylistID string `json:"playlist_id"`
 }
+
+// ReconcilePlaylistsPayload for queue messages
+type ReconcilePlaylistsPayload struct {
+	UserID uint `json:"user_ID"`
+}
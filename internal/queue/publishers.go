@@ -30,11 +30,15 @@ func (s *Service) AddPlaylist(mood *internal.Mood) error {
 }
 
 // UpdatePlaylist publishes a new message to the update_playlist queue
-func (s *Service) UpdatePlaylist(mood *internal.Mood) error {
+func (s *Service) UpdatePlaylist(mood *internal.Mood, clearDescription bool) error {
 	payload := model.UpdatePlaylistPayload{
-		UserID:     mood.UserID,
-		PlaylistID: mood.PlaylistID,
-		Name:       mood.Name,
+		UserID:           mood.UserID,
+		MoodID:           mood.ID,
+		PlaylistID:       mood.PlaylistID,
+		Name:             mood.Name,
+		Public:           &mood.Public,
+		Collaborative:    &mood.Collaborative,
+		ClearDescription: clearDescription,
 	}
 
 	if err := s.publishJSON(updatePlaylistQueue, payload); err != nil {
@@ -43,12 +47,73 @@ func (s *Service) UpdatePlaylist(mood *internal.Mood) error {
 	return nil
 }
 
+// RefreshPlaylist publishes a new message to the refresh_playlist queue
+func (s *Service) RefreshPlaylist(mood *internal.Mood) error {
+	payload := model.RefreshPlaylistPayload{
+		UserID: mood.UserID,
+		MoodID: mood.ID,
+	}
+
+	if err := s.publishJSON(refreshPlaylistQueue, payload); err != nil {
+		return err
+	}
+	return nil
+}
+
+// UploadCover publishes a new message to the upload_cover queue
+func (s *Service) UploadCover(mood *internal.Mood) error {
+	payload := model.UploadCoverPayload{
+		UserID: mood.UserID,
+		MoodID: mood.ID,
+	}
+
+	if err := s.publishJSON(uploadCoverQueue, payload); err != nil {
+		return err
+	}
+	return nil
+}
+
+// RestoreSnapshot publishes a new message to the restore_snapshot queue
+func (s *Service) RestoreSnapshot(mood *internal.Mood, snapshot *internal.Snapshot, actor *internal.User) error {
+	payload := model.RestoreSnapshotPayload{
+		UserID:     mood.UserID,
+		MoodID:     mood.ID,
+		SnapshotID: snapshot.ID,
+		ActorID:    actor.ID,
+	}
+
+	if err := s.publishJSON(restoreSnapshotQueue, payload); err != nil {
+		return err
+	}
+	return nil
+}
+
 // DeletePlaylist publishes a new message to the delete_playlist queue
-func (s *Service) DeletePlaylist(userID uint, playlistID string) error {
-	payload := model.DeletePlaylistPayload{UserID: userID, PlaylistID: playlistID}
+func (s *Service) DeletePlaylist(mood *internal.Mood) error {
+	payload := model.DeletePlaylistPayload{UserID: mood.UserID, MoodID: mood.ID, PlaylistID: mood.PlaylistID}
 
 	if err := s.publishJSON(deletePlaylistQueue, payload); err != nil {
 		return err
 	}
 	return nil
 }
+
+// SyncHistory publishes a new message to the sync_history queue
+func (s *Service) SyncHistory(userID uint) error {
+	payload := model.SyncHistoryPayload{UserID: userID}
+
+	if err := s.publishJSON(syncHistoryQueue, payload); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ReconcilePlaylists publishes a new message to the reconcile_playlists queue
+func (s *Service) ReconcilePlaylists(userID uint) error {
+	payload := model.ReconcilePlaylistsPayload{UserID: userID}
+
+	if err := s.publishJSON(reconcileQueue, payload); err != nil {
+		return err
+	}
+	return nil
+}
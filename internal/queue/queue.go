@@ -9,13 +9,18 @@ import (
 )
 
 const (
-	pingQueue           = "ping"
-	addPlaylistQueue    = "add_playlist"
-	updatePlaylistQueue = "update_playlist"
-	deletePlaylistQueue = "delete_playlist"
+	pingQueue            = "ping"
+	addPlaylistQueue     = "add_playlist"
+	updatePlaylistQueue  = "update_playlist"
+	refreshPlaylistQueue = "refresh_playlist"
+	deletePlaylistQueue  = "delete_playlist"
+	syncHistoryQueue     = "sync_history"
+	reconcileQueue       = "reconcile_playlists"
+	uploadCoverQueue     = "upload_cover"
+	restoreSnapshotQueue = "restore_snapshot"
 )
 
-var durableQueues = []string{addPlaylistQueue, updatePlaylistQueue, deletePlaylistQueue}
+var durableQueues = []string{addPlaylistQueue, updatePlaylistQueue, refreshPlaylistQueue, deletePlaylistQueue, syncHistoryQueue, reconcileQueue, uploadCoverQueue, restoreSnapshotQueue}
 
 // Service to manage working with the queue
 type Service struct {
@@ -109,6 +114,19 @@ func Listen(s *Service, h *Handler) error {
 		return fmt.Errorf("Failed to register consumer: %v", err)
 	}
 
+	refreshPlaylistMsgs, err := s.ch.Consume(
+		refreshPlaylistQueue, // queue
+		"",                   // consumer
+		false,                // auto-ack
+		false,                // exclusive
+		false,                // no-local
+		false,                // no-wait
+		nil,                  // args
+	)
+	if err != nil {
+		return fmt.Errorf("Failed to register consumer: %v", err)
+	}
+
 	deletePlaylistMsgs, err := s.ch.Consume(
 		deletePlaylistQueue, // queue
 		"",                  // consumer
@@ -122,12 +140,69 @@ func Listen(s *Service, h *Handler) error {
 		return fmt.Errorf("Failed to register consumer: %v", err)
 	}
 
+	syncHistoryMsgs, err := s.ch.Consume(
+		syncHistoryQueue, // queue
+		"",               // consumer
+		false,            // auto-ack
+		false,            // exclusive
+		false,            // no-local
+		false,            // no-wait
+		nil,              // args
+	)
+	if err != nil {
+		return fmt.Errorf("Failed to register consumer: %v", err)
+	}
+
+	reconcileMsgs, err := s.ch.Consume(
+		reconcileQueue, // queue
+		"",             // consumer
+		false,          // auto-ack
+		false,          // exclusive
+		false,          // no-local
+		false,          // no-wait
+		nil,            // args
+	)
+	if err != nil {
+		return fmt.Errorf("Failed to register consumer: %v", err)
+	}
+
+	uploadCoverMsgs, err := s.ch.Consume(
+		uploadCoverQueue, // queue
+		"",               // consumer
+		false,            // auto-ack
+		false,            // exclusive
+		false,            // no-local
+		false,            // no-wait
+		nil,              // args
+	)
+	if err != nil {
+		return fmt.Errorf("Failed to register consumer: %v", err)
+	}
+
+	restoreSnapshotMsgs, err := s.ch.Consume(
+		restoreSnapshotQueue, // queue
+		"",                   // consumer
+		false,                // auto-ack
+		false,                // exclusive
+		false,                // no-local
+		false,                // no-wait
+		nil,                  // args
+	)
+	if err != nil {
+		return fmt.Errorf("Failed to register consumer: %v", err)
+	}
+
 	untilErr := make(chan error)
 
 	go handleMessages(pings, h.handlePing)
 	go handleMessages(addPlaylistMsgs, h.handleAddPlaylist)
 	go handleMessages(updatePlaylistMsgs, h.handleUpdatePlaylist)
+	go handleMessages(refreshPlaylistMsgs, h.handleRefreshPlaylist)
 	go handleMessages(deletePlaylistMsgs, h.handleDeletePlaylist)
+	go handleMessages(syncHistoryMsgs, h.handleSyncHistory)
+	go handleMessages(reconcileMsgs, h.handleReconcilePlaylists)
+	go handleMessages(uploadCoverMsgs, h.handleUploadCover)
+	go handleMessages(restoreSnapshotMsgs, h.handleRestoreSnapshot)
 
 	return <-untilErr
 }
@@ -0,0 +1,47 @@
+package scheduler
+
+import (
+	"log"
+
+	"github.com/spf13/viper"
+)
+
+// syncHistory queues a listening history sync for every active user
+func (s *Scheduler) syncHistory() error {
+	users, err := s.services.User().FindActive(viper.GetDuration("scheduler.active_within"))
+	if err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		if err := s.services.Queue().SyncHistory(user.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reconcilePlaylists queues a drift check of the mood playlists of every active user
+func (s *Scheduler) reconcilePlaylists() error {
+	users, err := s.services.User().FindActive(viper.GetDuration("scheduler.active_within"))
+	if err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		if err := s.services.Queue().ReconcilePlaylists(user.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sweepMoods queues playlist jobs again for moods whose jobs were lost, deletes playlists of trashed moods
+// and reports what was done
+func (s *Scheduler) sweepMoods() error {
+	report, err := s.services.Mood().Sweep(viper.GetDuration("scheduler.sweep_after"), viper.GetDuration("moods.undo_window"))
+	if report != nil && (len(report.Requeued) > 0 || len(report.Deleted) > 0) {
+		log.Printf("Swept moods, playlist creation queued again for: %v, playlist deletion queued for: %v", report.Requeued, report.Deleted)
+	}
+	return err
+}
@@ -0,0 +1,43 @@
+package scheduler
+
+import (
+	"log"
+	"time"
+
+	"github.com/flexicon/spotimoods-go/internal"
+	"github.com/spf13/viper"
+)
+
+// Scheduler periodically publishes jobs for the worker to handle
+type Scheduler struct {
+	services *internal.ServiceProvider
+}
+
+// New constructor
+func New(services *internal.ServiceProvider) *Scheduler {
+	return &Scheduler{services: services}
+}
+
+// Start runs every periodic job in the background
+func (s *Scheduler) Start() {
+	go every("sync_history", viper.GetDuration("scheduler.history_interval"), s.syncHistory)
+	go every("reconcile_playlists", viper.GetDuration("scheduler.drift_interval"), s.reconcilePlaylists)
+	go every("sweep_moods", viper.GetDuration("scheduler.sweep_interval"), s.sweepMoods)
+}
+
+// every runs the given job at the given interval, a zero interval disables the job
+func every(name string, interval time.Duration, job func() error) {
+	if interval <= 0 {
+		log.Printf("Scheduled job '%s' is disabled", name)
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := job(); err != nil {
+			log.Printf("Scheduled job '%s' failed: %v", name, err)
+		}
+	}
+}
@@ -13,6 +13,10 @@ type HTTPClient interface {
 type RepositoryProvider interface {
 	User() UserRepository
 	Mood() MoodRepository
+	Block() BlockRepository
+	Play() PlayRepository
+	Snapshot() SnapshotRepository
+	Audit() AuditRepository
 }
 
 // ServiceProvider manages all services
@@ -45,7 +49,22 @@ func (p *ServiceProvider) Spotify() SpotifyClient {
 
 // Mood returns a new Mood service
 func (p *ServiceProvider) Mood() *MoodService {
-	return NewMoodService(p.repos.Mood(), p.Queue(), p.Spotify())
+	return NewMoodService(p.repos.Mood(), p.repos.Block(), p.repos.Snapshot(), p.repos.Audit(), p.Queue(), p.Spotify())
+}
+
+// Block returns a new Block service
+func (p *ServiceProvider) Block() *BlockService {
+	return NewBlockService(p.repos.Block(), p.repos.Mood(), p.Queue())
+}
+
+// History returns a new History service
+func (p *ServiceProvider) History() *HistoryService {
+	return NewHistoryService(p.repos.Play(), p.repos.Mood(), p.Spotify())
+}
+
+// Stats returns a new Stats service
+func (p *ServiceProvider) Stats() *StatsService {
+	return NewStatsService(p.repos.Mood(), p.repos.Play(), p.Spotify(), p.Cache())
 }
 
 // Queue returns the Queue service instance
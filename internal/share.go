@@ -0,0 +1,132 @@
+package internal
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"time"
+)
+
+// shareTokenBytes of randomness behind every share token
+const shareTokenBytes = 24
+
+// SharedMood is the read-only view of a published mood
+type SharedMood struct {
+	Name        string           `json:"name"`
+	Color       string           `json:"color"`
+	Artists     []*SpotifyArtist `json:"artists"`
+	PlaylistURL string           `json:"playlist_url,omitempty"`
+	SharedAt    *time.Time       `json:"shared_at"`
+}
+
+// ShareForUser publishes the mood by the given ID and user under a new share token,
+// which replaces any previous token so that old links stop working
+func (s *MoodService) ShareForUser(id uint, user *User) (*Mood, error) {
+	mood, err := s.FindForUser(id, user)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := newShareToken()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	mood.ShareToken = &token
+	mood.SharedAt = &now
+
+	if err := s.r.Update(mood, "ShareToken", "SharedAt"); err != nil {
+		return nil, err
+	}
+
+	return mood, nil
+}
+
+// UnshareForUser revokes the share token of the mood by the given ID and user
+func (s *MoodService) UnshareForUser(id uint, user *User) (*Mood, error) {
+	mood, err := s.FindForUser(id, user)
+	if err != nil {
+		return nil, err
+	}
+
+	mood.ShareToken = nil
+	mood.SharedAt = nil
+
+	if err := s.r.Update(mood, "ShareToken", "SharedAt"); err != nil {
+		return nil, err
+	}
+
+	return mood, nil
+}
+
+// FindShared finds a published mood by the given share token
+func (s *MoodService) FindShared(token string) (*Mood, error) {
+	return s.r.FindByShareToken(token)
+}
+
+// GetShared builds the read-only view of the mood published under the given share token.
+// Spotify data is fetched with app-level credentials, since the viewer need not be logged in.
+func (s *MoodService) GetShared(token string) (*SharedMood, error) {
+	mood, err := s.FindShared(token)
+	if err != nil {
+		return nil, err
+	}
+
+	appToken, err := s.spotify.GetAppToken()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := IDsByType(mood.Tags, TagTypeArtist)
+	artists, _, err := s.spotify.GetArtistsByIDs(appToken, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	shared := &SharedMood{
+		Name:     mood.Name,
+		Color:    mood.Color,
+		Artists:  make([]*SpotifyArtist, 0, len(ids)),
+		SharedAt: mood.SharedAt,
+	}
+	for _, id := range ids {
+		if artist, ok := artists[id]; ok {
+			shared.Artists = append(shared.Artists, artist)
+		}
+	}
+	if mood.PlaylistID != "" {
+		shared.PlaylistURL = fmt.Sprintf("https://open.spotify.com/playlist/%s", mood.PlaylistID)
+	}
+
+	return shared, nil
+}
+
+// CloneForUser copies the name, color, tags and settings of the given mood into a new mood owned by the given user,
+// queueing the creation of its own playlist
+func (s *MoodService) CloneForUser(source *Mood, user *User) (*Mood, error) {
+	mood := &Mood{
+		Name:          source.Name,
+		Color:         source.Color,
+		Description:   source.Description,
+		CoverInitials: source.CoverInitials,
+		Public:        source.Public,
+		Collaborative: source.Collaborative,
+		Features:      source.Features,
+		Tags:          make([]Tag, 0, len(source.Tags)),
+	}
+	for _, tag := range source.Tags {
+		mood.Tags = append(mood.Tags, Tag{Type: tag.Type, SpotifyID: tag.SpotifyID})
+	}
+
+	return s.AddMood(mood, user)
+}
+
+// newShareToken generates an unguessable, url safe token
+func newShareToken() (string, error) {
+	b := make([]byte, shareTokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate share token: %v", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,260 @@
+package internal
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// maxSnapshots kept for every mood, older ones are dropped as new ones are taken
+const maxSnapshots = 20
+
+// Snapshot of a mood and its playlist tracks, taken before the playlist is replaced
+type Snapshot struct {
+	ID        uint      `gorm:"primary_key" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	MoodID    uint      `gorm:"not null;index" json:"mood_id"`
+	// SpotifySnapshotID of the playlist version the tracks were read from
+	SpotifySnapshotID string        `json:"snapshot_id"`
+	State             SnapshotState `gorm:"type:mediumtext" json:"state"`
+}
+
+// TableName of mood snapshots
+func (Snapshot) TableName() string {
+	return "mood_snapshots"
+}
+
+// SnapshotState of a mood, stored as JSON
+type SnapshotState struct {
+	TrackURIs  []string     `json:"track_uris"`
+	DurationMs int          `json:"duration_ms"`
+	Tags       []Tag        `json:"tags"`
+	Settings   MoodSettings `json:"settings"`
+	// SkippedLocal tracks of the playlist, left out as spotify does not take local files back
+	SkippedLocal int `json:"skipped_local"`
+}
+
+// Value for storing the state in the DB
+func (s SnapshotState) Value() (driver.Value, error) {
+	b, err := json.Marshal(s)
+	return string(b), err
+}
+
+// Scan the state from the DB
+func (s *SnapshotState) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case []byte:
+		return json.Unmarshal(value, s)
+	case string:
+		return json.Unmarshal([]byte(value), s)
+	}
+
+	return fmt.Errorf("unsupported snapshot state type: %T", src)
+}
+
+// MoodSettings which shape a mood's playlist, apart from its tags
+type MoodSettings struct {
+	Name          string       `json:"name"`
+	Color         string       `json:"color"`
+	Description   string       `json:"description"`
+	Public        bool         `json:"public"`
+	Collaborative bool         `json:"collaborative"`
+	CoverInitials bool         `json:"cover_initials"`
+	Features      MoodFeatures `json:"features"`
+}
+
+// settingsFields of the mood, which snapshots restore
+var settingsFields = []string{"Name", "Color", "Description", "Public", "Collaborative", "CoverInitials", "Features"}
+
+// settings of the mood
+func (m *Mood) settings() MoodSettings {
+	return MoodSettings{
+		Name:          m.Name,
+		Color:         m.Color,
+		Description:   m.Description,
+		Public:        m.Public,
+		Collaborative: m.Collaborative,
+		CoverInitials: m.CoverInitials,
+		Features:      m.Features,
+	}
+}
+
+// apply the settings onto the given mood
+func (s MoodSettings) apply(mood *Mood) {
+	mood.Name = s.Name
+	mood.Color = s.Color
+	mood.Description = s.Description
+	mood.Public = s.Public
+	mood.Collaborative = s.Collaborative
+	mood.CoverInitials = s.CoverInitials
+	mood.Features = s.Features
+}
+
+// SnapshotRepository for interacting with mood snapshot data
+type SnapshotRepository interface {
+	// FindByMood lists the snapshots of the given mood, from the latest
+	FindByMood(mood *Mood) ([]*Snapshot, error)
+	// FindForMood the snapshot by the given ID, if it belongs to the given mood
+	FindForMood(id uint, mood *Mood) (*Snapshot, error)
+	// Save persists the given snapshot
+	Save(snapshot *Snapshot) error
+	// Prune the snapshots of the given mood down to the given amount of latest ones
+	Prune(mood *Mood, keep int) error
+}
+
+// GetSnapshots lists the snapshots of the given mood, from the latest
+func (s *MoodService) GetSnapshots(mood *Mood) ([]*Snapshot, error) {
+	return s.snapshots.FindByMood(mood)
+}
+
+// FindSnapshot by the given ID, if it belongs to the given mood
+func (s *MoodService) FindSnapshot(id uint, mood *Mood) (*Snapshot, error) {
+	return s.snapshots.FindForMood(id, mood)
+}
+
+// QueueRestoreForUser of the snapshot by the given ID, to be restored by the worker on behalf of the given user
+func (s *MoodService) QueueRestoreForUser(snapshotID uint, mood *Mood, user *User) (*Snapshot, error) {
+	if mood.PlaylistID == "" {
+		return nil, ErrNoPlaylist
+	}
+	snapshot, err := s.snapshots.FindForMood(snapshotID, mood)
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshot, s.q.RestoreSnapshot(mood, snapshot, user)
+}
+
+// RestoreSnapshot rewrites the mood by the given ID and its playlist to the state of the given snapshot.
+// The current state is kept as a snapshot of its own, so that a restore can be undone as well.
+// The playlist is written first, the mood is only changed once spotify has taken the restored state.
+// The restore is audited as made by the user of the given actor ID, who requested it.
+func (s *MoodService) RestoreSnapshot(moodID, snapshotID, actorID uint, token *SpotifyToken) error {
+	mood, err := s.Find(moodID)
+	if err != nil {
+		return err
+	}
+	if mood.PlaylistID == "" {
+		return ErrNoPlaylist
+	}
+	snapshot, err := s.snapshots.FindForMood(snapshotID, mood)
+	if err != nil {
+		return err
+	}
+
+	current, err := s.readSnapshot(mood, token)
+	if err != nil {
+		return err
+	}
+
+	tags := make([]Tag, 0, len(snapshot.State.Tags))
+	for _, tag := range snapshot.State.Tags {
+		tags = append(tags, Tag{Type: tag.Type, SpotifyID: tag.SpotifyID})
+	}
+	now := time.Now()
+	restored := *mood
+	snapshot.State.Settings.apply(&restored)
+	restored.Tags = tags
+	restored.TrackCount = len(snapshot.State.TrackURIs)
+	restored.DurationMs = snapshot.State.DurationMs
+	restored.SyncedAt = &now
+
+	if err := s.spotify.SetPlaylistTracks(token, mood.PlaylistID, snapshot.State.TrackURIs); err != nil {
+		return err
+	}
+	details, err := s.playlistDetails(&restored, token)
+	if err != nil {
+		return err
+	}
+	if restored.Description == "" && mood.Description != "" {
+		details.Description = new(string)
+	}
+	if err := s.spotify.UpdatePlaylist(token, mood.PlaylistID, details); err != nil {
+		return err
+	}
+
+	if err := s.keepSnapshot(mood, current); err != nil {
+		return err
+	}
+	if err := s.r.Update(&restored, settingsFields...); err != nil {
+		return err
+	}
+	if err := s.r.ReplaceTags(&restored, tags); err != nil {
+		return err
+	}
+	if err := s.r.UpdateSync(&restored); err != nil {
+		return err
+	}
+	// The worker restores the snapshot, but the user who requested it is recorded as the actor
+	actor := &User{}
+	actor.ID = actorID
+	s.record(&restored, AuditActionUpdate, AuditSourceWorker, actor, diffAudit(mood.auditState(), restored.auditState()))
+
+	if restored.coverOptions() == mood.coverOptions() {
+		return nil
+	}
+	return s.q.UploadCover(&restored)
+}
+
+// takeSnapshot of the given mood along with the current tracks of its playlist, unless the playlist is still empty
+func (s *MoodService) takeSnapshot(mood *Mood, token *SpotifyToken) error {
+	snapshot, err := s.readSnapshot(mood, token)
+	if err != nil {
+		return err
+	}
+
+	return s.keepSnapshot(mood, snapshot)
+}
+
+// readSnapshot of the given mood along with the current tracks of its playlist, without storing it.
+// Nothing is read while the playlist is still empty.
+func (s *MoodService) readSnapshot(mood *Mood, token *SpotifyToken) (*Snapshot, error) {
+	if mood.PlaylistID == "" {
+		return nil, nil
+	}
+
+	playlist, err := s.spotify.GetPlaylist(token, mood.PlaylistID)
+	if err != nil {
+		return nil, err
+	}
+	tracks, err := s.GetPlaylistTracks(mood, token)
+	if err != nil {
+		return nil, err
+	}
+	if len(tracks) == 0 {
+		return nil, nil
+	}
+
+	state := SnapshotState{
+		TrackURIs: make([]string, 0, len(tracks)),
+		Tags:      mood.Tags,
+		Settings:  mood.settings(),
+	}
+	for _, track := range tracks {
+		if track.IsLocal {
+			state.SkippedLocal++
+			continue
+		}
+		state.TrackURIs = append(state.TrackURIs, track.URI)
+		state.DurationMs += track.DurationMs
+	}
+
+	return &Snapshot{
+		MoodID:            mood.ID,
+		SpotifySnapshotID: playlist.SnapshotID,
+		State:             state,
+	}, nil
+}
+
+// keepSnapshot of the given mood, if there is one, pruning the oldest ones
+func (s *MoodService) keepSnapshot(mood *Mood, snapshot *Snapshot) error {
+	if snapshot == nil {
+		return nil
+	}
+	if err := s.snapshots.Save(snapshot); err != nil {
+		return err
+	}
+
+	return s.snapshots.Prune(mood, maxSnapshots)
+}
@@ -56,6 +56,203 @@ type SpotifyArtist struct {
 	} `json:"external_urls"`
 }
 
+// SpotifyTrack response structure
+//
+// Docs: https://developer.spotify.com/documentation/web-api/reference/tracks/
+type SpotifyTrack struct {
+	ID           string          `json:"id"`
+	Name         string          `json:"name"`
+	URI          string          `json:"uri"`
+	Link         string          `json:"href"`
+	DurationMs   int             `json:"duration_ms"`
+	Explicit     bool            `json:"explicit"`
+	Popularity   int             `json:"popularity"`
+	PreviewURL   string          `json:"preview_url"`
+	IsLocal      bool            `json:"is_local"`
+	Artists      []SpotifyArtist `json:"artists"`
+	Album        *SpotifyAlbum   `json:"album,omitempty"`
+	ExternalURLs struct {
+		Spotify string `json:"spotify"`
+	} `json:"external_urls"`
+}
+
+// SpotifyAlbum response structure
+//
+// Docs: https://developer.spotify.com/documentation/web-api/reference/albums/
+type SpotifyAlbum struct {
+	ID           string          `json:"id"`
+	Name         string          `json:"name"`
+	URI          string          `json:"uri"`
+	Link         string          `json:"href"`
+	AlbumType    string          `json:"album_type"`
+	ReleaseDate  string          `json:"release_date"`
+	TotalTracks  int             `json:"total_tracks"`
+	Images       []SpotifyImage  `json:"images"`
+	Artists      []SpotifyArtist `json:"artists"`
+	ExternalURLs struct {
+		Spotify string `json:"spotify"`
+	} `json:"external_urls"`
+}
+
+// SpotifyPlaylist response structure
+//
+// Docs: https://developer.spotify.com/documentation/web-api/reference/playlists/
+type SpotifyPlaylist struct {
+	ID            string         `json:"id"`
+	Name          string         `json:"name"`
+	Description   string         `json:"description"`
+	URI           string         `json:"uri"`
+	Link          string         `json:"href"`
+	Collaborative bool           `json:"collaborative"`
+	Public        bool           `json:"public"`
+	SnapshotID    string         `json:"snapshot_id"`
+	Images        []SpotifyImage `json:"images"`
+	Owner         struct {
+		ID          string `json:"id"`
+		DisplayName string `json:"display_name"`
+	} `json:"owner"`
+	Tracks struct {
+		Total int `json:"total"`
+	} `json:"tracks"`
+	ExternalURLs struct {
+		Spotify string `json:"spotify"`
+	} `json:"external_urls"`
+}
+
+// SpotifyPageOptions for requesting a specific page of a paginated Spotify endpoint
+type SpotifyPageOptions struct {
+	Limit  int
+	Offset int
+	// TimeRange of the affinity data for personalization endpoints: short_term, medium_term or long_term
+	TimeRange string
+}
+
+// SpotifyPage metadata of a paginated Spotify response
+//
+// Docs: https://developer.spotify.com/documentation/web-api/reference/object-model/#paging-object
+type SpotifyPage struct {
+	Total    int    `json:"total"`
+	Limit    int    `json:"limit"`
+	Offset   int    `json:"offset"`
+	Next     string `json:"next"`
+	Previous string `json:"previous"`
+}
+
+// HasNext checks whether there is a page after this one
+func (p SpotifyPage) HasNext() bool {
+	return p.Next != ""
+}
+
+// SpotifyArtistPage of artists
+type SpotifyArtistPage struct {
+	SpotifyPage
+	Items []*SpotifyArtist `json:"items"`
+}
+
+// SpotifyTrackPage of tracks
+type SpotifyTrackPage struct {
+	SpotifyPage
+	Items []*SpotifyTrack `json:"items"`
+}
+
+// SpotifyAlbumPage of albums
+type SpotifyAlbumPage struct {
+	SpotifyPage
+	Items []*SpotifyAlbum `json:"items"`
+}
+
+// SpotifyPlaylistPage of playlists
+type SpotifyPlaylistPage struct {
+	SpotifyPage
+	Items []*SpotifyPlaylist `json:"items"`
+}
+
+// SpotifyPlaylistItem of a playlist, where the track is nil for items spotify can no longer play
+type SpotifyPlaylistItem struct {
+	AddedAt time.Time     `json:"added_at"`
+	Track   *SpotifyTrack `json:"track"`
+}
+
+// SpotifyPlaylistItemPage of playlist items
+type SpotifyPlaylistItemPage struct {
+	SpotifyPage
+	Items []*SpotifyPlaylistItem `json:"items"`
+}
+
+// Spotify search types
+const (
+	SearchTypeArtist   = "artist"
+	SearchTypeTrack    = "track"
+	SearchTypeAlbum    = "album"
+	SearchTypePlaylist = "playlist"
+)
+
+// SpotifySearchResult holds a page for every type that was searched for
+//
+// Docs: https://developer.spotify.com/documentation/web-api/reference/search/search/
+type SpotifySearchResult struct {
+	Artists   *SpotifyArtistPage   `json:"artists,omitempty"`
+	Tracks    *SpotifyTrackPage    `json:"tracks,omitempty"`
+	Albums    *SpotifyAlbumPage    `json:"albums,omitempty"`
+	Playlists *SpotifyPlaylistPage `json:"playlists,omitempty"`
+}
+
+// SpotifyCursorOptions for requesting a specific page of a cursor paginated Spotify endpoint
+type SpotifyCursorOptions struct {
+	Limit  int
+	After  time.Time
+	Before time.Time
+}
+
+// SpotifyPlayHistory item of a recently played track
+//
+// Docs: https://developer.spotify.com/documentation/web-api/reference/object-model/#play-history-object
+type SpotifyPlayHistory struct {
+	Track    SpotifyTrack `json:"track"`
+	PlayedAt time.Time    `json:"played_at"`
+}
+
+// SpotifyPlayHistoryPage of recently played tracks
+type SpotifyPlayHistoryPage struct {
+	Items   []*SpotifyPlayHistory `json:"items"`
+	Limit   int                   `json:"limit"`
+	Next    string                `json:"next"`
+	Cursors struct {
+		After  string `json:"after"`
+		Before string `json:"before"`
+	} `json:"cursors"`
+}
+
+// SpotifyCurrentlyPlaying state of the user's playback
+//
+// Docs: https://developer.spotify.com/documentation/web-api/reference/player/get-the-users-currently-playing-track/
+type SpotifyCurrentlyPlaying struct {
+	IsPlaying  bool   `json:"is_playing"`
+	ProgressMs int    `json:"progress_ms"`
+	Type       string `json:"currently_playing_type"`
+	// Item is only set when a track is playing, rather than e.g. an episode or an ad
+	Item *SpotifyTrack `json:"item"`
+}
+
+// SpotifyAudioFeatures of a track
+//
+// Docs: https://developer.spotify.com/documentation/web-api/reference/tracks/get-audio-features/
+type SpotifyAudioFeatures struct {
+	ID           string  `json:"id"`
+	Energy       float64 `json:"energy"`
+	Valence      float64 `json:"valence"`
+	Danceability float64 `json:"danceability"`
+	Acousticness float64 `json:"acousticness"`
+	Tempo        float64 `json:"tempo"`
+}
+
+// SpotifyRecommendationSeeds to base recommendations on, up to 5 seeds in total
+type SpotifyRecommendationSeeds struct {
+	Artists []string
+	Tracks  []string
+	Genres  []string
+}
+
 // SpotifyImage structure
 type SpotifyImage struct {
 	Height int    `json:"height"`
@@ -63,6 +260,14 @@ type SpotifyImage struct {
 	URL    string `json:"url"`
 }
 
+// PlaylistDetails to create or update a playlist with, where nil fields are left as they are
+type PlaylistDetails struct {
+	Name          string
+	Description   *string
+	Public        *bool
+	Collaborative *bool
+}
+
 // CreatePlaylistResponse from the spotify API
 //
 // Docs: https://developer.spotify.com/documentation/web-api/reference/playlists/create-playlist/
@@ -79,16 +284,56 @@ type SpotifyClient interface {
 	GetMyProfile(token *SpotifyToken) (*SpotifyProfile, error)
 	// AuthorizeByCode with Spotify and return a token response
 	AuthorizeByCode(code string) (*SpotifyTokenResponse, error)
+	// GetAppToken retrieves an app-level token, for requests made on behalf of no user
+	GetAppToken() (*SpotifyToken, error)
 	// CreatePlaylist makes a new playlist for the authed user and returns it's ID
-	CreatePlaylist(token *SpotifyToken, name string) (string, error)
+	CreatePlaylist(token *SpotifyToken, details PlaylistDetails) (string, error)
 	// UpdatePlaylist edits an existing playlist for the authed user
-	UpdatePlaylist(token *SpotifyToken, id, name string) error
+	UpdatePlaylist(token *SpotifyToken, id string, details PlaylistDetails) error
 	// DeletePlaylist for the authed user
 	DeletePlaylist(token *SpotifyToken, id string) error
 	// SearchForArtists by the given query
-	SearchForArtists(token *SpotifyToken, query string) ([]*SpotifyArtist, error)
+	SearchForArtists(token *SpotifyToken, query string, opts SpotifyPageOptions) (*SpotifyArtistPage, error)
+	// SearchForTracks by the given query
+	SearchForTracks(token *SpotifyToken, query string, opts SpotifyPageOptions) (*SpotifyTrackPage, error)
+	// SearchForAlbums by the given query
+	SearchForAlbums(token *SpotifyToken, query string, opts SpotifyPageOptions) (*SpotifyAlbumPage, error)
+	// Search for any combination of the given search types by the given query
+	Search(token *SpotifyToken, query string, types []string, opts SpotifyPageOptions) (*SpotifySearchResult, error)
 	// GetTopArtists for the user
-	GetTopArtists(token *SpotifyToken) ([]*SpotifyArtist, error)
-	// GetArtistsByIDs retrieves the artists related to the given IDs
-	GetArtistsByIDs(token *SpotifyToken, ids []string) ([]*SpotifyArtist, error)
+	GetTopArtists(token *SpotifyToken, opts SpotifyPageOptions) (*SpotifyArtistPage, error)
+	// GetTopTracks for the user
+	GetTopTracks(token *SpotifyToken, opts SpotifyPageOptions) (*SpotifyTrackPage, error)
+	// GetArtistsByIDs retrieves the artists related to the given IDs keyed by ID, along with any IDs that Spotify did not find
+	GetArtistsByIDs(token *SpotifyToken, ids []string) (map[string]*SpotifyArtist, []string, error)
+	// GetTracksByIDs retrieves the tracks related to the given IDs keyed by ID, along with any IDs that Spotify did not find
+	GetTracksByIDs(token *SpotifyToken, ids []string) (map[string]*SpotifyTrack, []string, error)
+	// GetAlbumsByIDs retrieves the albums related to the given IDs keyed by ID, along with any IDs that Spotify did not find
+	GetAlbumsByIDs(token *SpotifyToken, ids []string) (map[string]*SpotifyAlbum, []string, error)
+	// GetArtistTopTracks retrieves the most popular tracks of the given artist
+	GetArtistTopTracks(token *SpotifyToken, artistID string) ([]*SpotifyTrack, error)
+	// GetAlbumTracks retrieves the tracks of the given album
+	GetAlbumTracks(token *SpotifyToken, albumID string) ([]*SpotifyTrack, error)
+	// GetCurrentlyPlaying track of the user, nil when nothing is playing
+	GetCurrentlyPlaying(token *SpotifyToken) (*SpotifyCurrentlyPlaying, error)
+	// GetAudioFeatures of the given track
+	GetAudioFeatures(token *SpotifyToken, trackID string) (*SpotifyAudioFeatures, error)
+	// GetRecentlyPlayed tracks of the user
+	GetRecentlyPlayed(token *SpotifyToken, opts SpotifyCursorOptions) (*SpotifyPlayHistoryPage, error)
+	// GetGenreSeeds lists the genres available for recommendations
+	GetGenreSeeds(token *SpotifyToken) ([]string, error)
+	// GetRecommendations of tracks based on the given seeds
+	GetRecommendations(token *SpotifyToken, seeds SpotifyRecommendationSeeds, limit int) ([]*SpotifyTrack, error)
+	// SetPlaylistTracks replaces all tracks of the given playlist with the given track URIs
+	SetPlaylistTracks(token *SpotifyToken, id string, uris []string) error
+	// GetPlaylist retrieves the details of the given playlist
+	GetPlaylist(token *SpotifyToken, id string) (*SpotifyPlaylist, error)
+	// IsFollowingPlaylist checks whether the user of the given token still follows the given playlist
+	IsFollowingPlaylist(token *SpotifyToken, id string) (bool, error)
+	// FollowPlaylist for the user of the given token
+	FollowPlaylist(token *SpotifyToken, id string) error
+	// UploadPlaylistCover replaces the cover image of the given playlist with the given JPEG
+	UploadPlaylistCover(token *SpotifyToken, id string, jpeg []byte) error
+	// GetPlaylistItems retrieves a page of the items of the given playlist
+	GetPlaylistItems(token *SpotifyToken, id string, opts SpotifyPageOptions) (*SpotifyPlaylistItemPage, error)
 }
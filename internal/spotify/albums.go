@@ -0,0 +1,120 @@
+package spotify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/flexicon/spotimoods-go/internal"
+	"github.com/pkg/errors"
+)
+
+// maxAlbumIDs is the amount of IDs the several albums endpoint accepts in a single request
+const maxAlbumIDs = 20
+
+// GetAlbumsByIDs retrieves the albums related to the given IDs, keyed by their ID.
+// IDs for which Spotify returned no album are reported in the second return value.
+func (c *Client) GetAlbumsByIDs(token *internal.SpotifyToken, ids []string) (map[string]*internal.SpotifyAlbum, []string, error) {
+	// First check cache for albums and only make requests if any non-cached albums remain
+	albums, remainingIDs := c.getAndFilterCachedAlbums(ids)
+	if len(remainingIDs) == 0 {
+		return albums, nil, nil
+	}
+
+	bodies, err := c.fetchInChunks(token, remainingIDs, maxAlbumIDs, func(chunk []string) string {
+		return fmt.Sprintf("https://api.spotify.com/v1/albums?ids=%s", strings.Join(chunk, ","))
+	})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to retrieve albums by ids")
+	}
+
+	missing := make([]string, 0)
+	for i, body := range bodies {
+		var response struct {
+			Albums []*internal.SpotifyAlbum `json:"albums"`
+		}
+		if err := json.NewDecoder(bytes.NewBuffer(body)).Decode(&response); err != nil {
+			return nil, nil, fmt.Errorf("error parsing albums response: %v", err)
+		}
+
+		// Spotify responds with albums in the order of the requested IDs, using null for unknown ones
+		for j, id := range chunkOf(remainingIDs, i, maxAlbumIDs) {
+			if j >= len(response.Albums) || response.Albums[j] == nil {
+				missing = append(missing, id)
+				continue
+			}
+			albums[id] = response.Albums[j]
+		}
+		c.cacheAlbums(response.Albums)
+	}
+
+	return albums, missing, nil
+}
+
+// GetAlbumTracks retrieves the tracks of the given album
+func (c *Client) GetAlbumTracks(token *internal.SpotifyToken, albumID string) ([]*internal.SpotifyTrack, error) {
+	tracksURL := fmt.Sprintf("https://api.spotify.com/v1/albums/%s/tracks?limit=50", albumID)
+	req, _ := http.NewRequest(http.MethodGet, tracksURL, nil)
+	cacheItem := &internal.CacheItem{
+		Key: fmt.Sprintf("GetAlbumTracks-%s", albumID),
+		TTL: time.Minute * 15,
+	}
+
+	body, err := c.fetchWithCache(req, token, cacheItem)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to retrieve album tracks")
+	}
+
+	var tracksResponse internal.SpotifyTrackPage
+	if err := json.NewDecoder(bytes.NewBuffer(body)).Decode(&tracksResponse); err != nil {
+		return nil, fmt.Errorf("error parsing album tracks response: %v", err)
+	}
+
+	return tracksResponse.Items, nil
+}
+
+// getAndFilterCachedAlbums tries to retrieve each album from cache by id,
+// returns the found albums keyed by id and a slice of remaining filtered ids that weren't found in cache
+func (c *Client) getAndFilterCachedAlbums(ids []string) (map[string]*internal.SpotifyAlbum, []string) {
+	albums := make(map[string]*internal.SpotifyAlbum)
+	remaining := make([]string, 0)
+
+	for _, id := range ids {
+		if _, ok := albums[id]; ok {
+			continue
+		}
+
+		var album *internal.SpotifyAlbum
+		err := c.cache.Get(fmt.Sprintf("Album-%s", id), &album)
+
+		if err == nil && album != nil {
+			albums[id] = album
+		} else if !contains(remaining, id) {
+			remaining = append(remaining, id)
+		}
+	}
+
+	return albums, remaining
+}
+
+func (c *Client) cacheAlbums(albums []*internal.SpotifyAlbum) {
+	for _, album := range albums {
+		if album == nil {
+			continue
+		}
+
+		err := c.cache.Set(&internal.CacheItem{
+			Key:   fmt.Sprintf("Album-%s", album.ID),
+			Value: &album,
+			TTL:   time.Minute * 15,
+		})
+
+		if err != nil {
+			log.Println(errors.Wrap(err, "failed to store album in cache"))
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"sync"
 	"time"
 
 	"github.com/flexicon/spotimoods-go/internal"
@@ -19,6 +20,9 @@ type Client struct {
 	http  internal.HTTPClient
 	repos internal.RepositoryProvider
 	cache internal.Cache
+
+	// tokenMu guards token fields which may be refreshed by concurrent requests
+	tokenMu sync.RWMutex
 }
 
 // NewClient constructor
@@ -77,8 +81,6 @@ func (c *Client) AuthorizeByCode(code string) (*internal.SpotifyTokenResponse, e
 
 // Authorize with Spotify and return a token response
 func (c *Client) Authorize(grant, grantName, grantType string) (*internal.SpotifyTokenResponse, error) {
-	clientID := viper.GetString("spotify.client_id")
-	clientSecret := viper.GetString("spotify.client_secret")
 	apiDomain := viper.GetString("domains.api")
 
 	form := url.Values{}
@@ -86,6 +88,44 @@ func (c *Client) Authorize(grant, grantName, grantType string) (*internal.Spotif
 	form.Set("grant_type", grantType)
 	form.Set("redirect_uri", fmt.Sprintf("%s/callback", apiDomain))
 
+	return c.requestToken(form)
+}
+
+// GetAppToken retrieves an app-level token through the client credentials flow, for requests made on behalf of no user
+func (c *Client) GetAppToken() (*internal.SpotifyToken, error) {
+	var accessToken string
+	err := c.cache.Once(&internal.CacheItem{
+		Key:   "GetAppToken",
+		Value: &accessToken,
+		// Spotify app tokens are valid for an hour, so they are dropped from cache well before expiring
+		TTL: 50 * time.Minute,
+		Do: func(*internal.CacheItem) (interface{}, error) {
+			form := url.Values{}
+			form.Set("grant_type", "client_credentials")
+
+			st, err := c.requestToken(form)
+			if err != nil {
+				return nil, err
+			}
+			if st.AccessToken == "" {
+				return nil, fmt.Errorf("failed to retrieve app token: %s", st.ErrorDescription)
+			}
+
+			return st.AccessToken, nil
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &internal.SpotifyToken{Token: accessToken}, nil
+}
+
+// requestToken from the spotify accounts service using the given form
+func (c *Client) requestToken(form url.Values) (*internal.SpotifyTokenResponse, error) {
+	clientID := viper.GetString("spotify.client_id")
+	clientSecret := viper.GetString("spotify.client_secret")
+
 	tokenURL := "https://accounts.spotify.com/api/token"
 	req, err := http.NewRequest(http.MethodPost, tokenURL, bytes.NewBuffer([]byte(form.Encode())))
 
@@ -112,8 +152,8 @@ func (c *Client) Authorize(grant, grantName, grantType string) (*internal.Spotif
 }
 
 // CreatePlaylist makes a new playlist for the authed user and returns it's ID
-func (c *Client) CreatePlaylist(token *internal.SpotifyToken, name string) (string, error) {
-	payload, err := json.Marshal(PlaylistPayload{Name: name})
+func (c *Client) CreatePlaylist(token *internal.SpotifyToken, details internal.PlaylistDetails) (string, error) {
+	payload, err := json.Marshal(newPlaylistPayload(details))
 	if err != nil {
 		return "", fmt.Errorf("failed to prepare payload: %v", err)
 	}
@@ -142,8 +182,8 @@ func (c *Client) CreatePlaylist(token *internal.SpotifyToken, name string) (stri
 }
 
 // UpdatePlaylist edits an existing playlist for the authed user
-func (c *Client) UpdatePlaylist(token *internal.SpotifyToken, id, name string) error {
-	payload, err := json.Marshal(PlaylistPayload{Name: name})
+func (c *Client) UpdatePlaylist(token *internal.SpotifyToken, id string, details internal.PlaylistDetails) error {
+	payload, err := json.Marshal(newPlaylistPayload(details))
 	if err != nil {
 		return fmt.Errorf("failed to prepare payload: %v", err)
 	}
@@ -173,42 +213,53 @@ func (c *Client) DeletePlaylist(token *internal.SpotifyToken, id string) error {
 	return nil
 }
 
-// SearchForArtists by the given query
-func (c *Client) SearchForArtists(token *internal.SpotifyToken, query string) ([]*internal.SpotifyArtist, error) {
-	searchURL, _ := url.Parse("https://api.spotify.com/v1/search")
-	q := url.Values{}
-	q.Add("q", query)
-	q.Add("type", "artist")
-	searchURL.RawQuery = q.Encode()
-
-	req, _ := http.NewRequest(http.MethodGet, searchURL.String(), nil)
-	cacheItem := &internal.CacheItem{
-		Key: fmt.Sprintf("SearchForArtists-user-%d-%s", token.UserID, searchURL.RawQuery),
-		TTL: time.Minute,
-	}
-
-	body, err := c.fetchWithCache(req, token, cacheItem)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to retrieve search results")
+// maxPlaylistTrackURIs is the amount of tracks that can be added to a playlist in a single request
+const maxPlaylistTrackURIs = 100
+
+// SetPlaylistTracks replaces all tracks of the given playlist with the given track URIs
+func (c *Client) SetPlaylistTracks(token *internal.SpotifyToken, id string, uris []string) error {
+	url := fmt.Sprintf("https://api.spotify.com/v1/playlists/%s/tracks", id)
+
+	// The first request replaces the playlist contents, even when there are no tracks, while any following ones append to it
+	method := http.MethodPut
+	for start := 0; start == 0 || start < len(uris); start += maxPlaylistTrackURIs {
+		end := start + maxPlaylistTrackURIs
+		if end > len(uris) {
+			end = len(uris)
+		}
+		chunk := append([]string{}, uris[start:end]...)
+
+		payload, err := json.Marshal(PlaylistTracksPayload{URIs: chunk})
+		if err != nil {
+			return fmt.Errorf("failed to prepare payload: %v", err)
+		}
+
+		req, err := http.NewRequest(method, url, bytes.NewBuffer(payload))
+		if err != nil {
+			return fmt.Errorf("failed to prepare request: %v", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := c.do(req, token)
+		if err != nil {
+			return fmt.Errorf("request failed when setting playlist tracks: %v", err)
+		}
+		resp.Body.Close()
+
+		method = http.MethodPost
 	}
 
-	var searchResponse struct {
-		Artists struct {
-			Items []*internal.SpotifyArtist `json:"items"`
-		} `json:"artists"`
-	}
-	if err := json.NewDecoder(bytes.NewBuffer(body)).Decode(&searchResponse); err != nil {
-		return nil, fmt.Errorf("error parsing artists response: %v", err)
-	}
-
-	return searchResponse.Artists.Items, nil
+	return nil
 }
 
 // GetTopArtists for the user
-func (c *Client) GetTopArtists(token *internal.SpotifyToken) ([]*internal.SpotifyArtist, error) {
-	req, _ := http.NewRequest(http.MethodGet, "https://api.spotify.com/v1/me/top/artists", nil)
+func (c *Client) GetTopArtists(token *internal.SpotifyToken, opts internal.SpotifyPageOptions) (*internal.SpotifyArtistPage, error) {
+	topURL, _ := url.Parse("https://api.spotify.com/v1/me/top/artists")
+	topURL.RawQuery = pageQuery(opts).Encode()
+
+	req, _ := http.NewRequest(http.MethodGet, topURL.String(), nil)
 	cacheItem := &internal.CacheItem{
-		Key: fmt.Sprintf("GetTopArtists-user-%d", token.UserID),
+		Key: fmt.Sprintf("GetTopArtists-user-%d-%s", token.UserID, topURL.RawQuery),
 		TTL: time.Minute,
 	}
 
@@ -217,18 +268,26 @@ func (c *Client) GetTopArtists(token *internal.SpotifyToken) ([]*internal.Spotif
 		return nil, errors.Wrap(err, "failed to retrieve top artists")
 	}
 
-	var topResponse struct {
-		Items []*internal.SpotifyArtist `json:"items"`
-	}
+	var topResponse internal.SpotifyArtistPage
 	if err := json.NewDecoder(bytes.NewBuffer(body)).Decode(&topResponse); err != nil {
 		return nil, fmt.Errorf("error parsing top artists response: %v", err)
 	}
 
-	return topResponse.Items, nil
+	return &topResponse, nil
 }
 
-// Refresh the given token with spotify
-func (c *Client) Refresh(token *internal.SpotifyToken) error {
+// Refresh the given token with spotify, unless it no longer holds the given stale access token.
+// Refreshes are serialised, as concurrent requests may all find the same token expired,
+// while spotify rotates the refresh token on every refresh.
+func (c *Client) Refresh(token *internal.SpotifyToken, stale string) error {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
+
+	// Another request already refreshed the token while this one was waiting
+	if token.Token != stale {
+		return nil
+	}
+
 	st, err := c.Authorize(token.Refresh, "refresh_token", "refresh_token")
 	if err != nil {
 		return err
@@ -5,8 +5,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
-	"net/http"
-	"net/url"
 	"strings"
 	"time"
 
@@ -14,49 +12,65 @@ import (
 	"github.com/pkg/errors"
 )
 
-// GetArtistsByIDs retrieves the artists related to the given IDs
-func (c *Client) GetArtistsByIDs(token *internal.SpotifyToken, ids []string) ([]*internal.SpotifyArtist, error) {
-	// First check cache for artists and only make a request if any non-cached artists remain
+// maxArtistIDs is the amount of IDs the several artists endpoint accepts in a single request
+const maxArtistIDs = 50
+
+// GetArtistsByIDs retrieves the artists related to the given IDs, keyed by their ID.
+// IDs for which Spotify returned no artist are reported in the second return value.
+func (c *Client) GetArtistsByIDs(token *internal.SpotifyToken, ids []string) (map[string]*internal.SpotifyArtist, []string, error) {
+	// First check cache for artists and only make requests if any non-cached artists remain
 	artists, remainingIDs := c.getAndFilterCachedArtists(ids)
 	if len(remainingIDs) == 0 {
-		return artists, nil
+		return artists, nil, nil
 	}
 
-	artistsURL, err := url.Parse(fmt.Sprintf("https://api.spotify.com/v1/artists?ids=%s", strings.Join(remainingIDs, ",")))
+	bodies, err := c.fetchInChunks(token, remainingIDs, maxArtistIDs, func(chunk []string) string {
+		return fmt.Sprintf("https://api.spotify.com/v1/artists?ids=%s", strings.Join(chunk, ","))
+	})
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to prepare artists by id url")
+		return nil, nil, errors.Wrap(err, "failed to retrieve artists by ids")
 	}
 
-	req, _ := http.NewRequest(http.MethodGet, artistsURL.String(), nil)
-	body, err := c.fetch(req, token)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to retrieve artists by ids")
-	}
+	missing := make([]string, 0)
+	for i, body := range bodies {
+		var response struct {
+			Artists []*internal.SpotifyArtist `json:"artists"`
+		}
+		if err := json.NewDecoder(bytes.NewBuffer(body)).Decode(&response); err != nil {
+			return nil, nil, fmt.Errorf("error parsing artists response: %v", err)
+		}
 
-	var response struct {
-		Artists []*internal.SpotifyArtist `json:"artists"`
-	}
-	if err := json.NewDecoder(bytes.NewBuffer(body)).Decode(&response); err != nil {
-		return nil, fmt.Errorf("error parsing artists response: %v", err)
+		// Spotify responds with artists in the order of the requested IDs, using null for unknown ones
+		for j, id := range chunkOf(remainingIDs, i, maxArtistIDs) {
+			if j >= len(response.Artists) || response.Artists[j] == nil {
+				missing = append(missing, id)
+				continue
+			}
+			artists[id] = response.Artists[j]
+		}
+		c.cacheArtists(response.Artists)
 	}
-	c.cacheArtists(response.Artists)
 
-	return append(artists, response.Artists...), nil
+	return artists, missing, nil
 }
 
 // getAndFilterCachedArtists tries to retrieve each artist from cache by id,
-// returns a slice of artists and a slice of remaining filtered ids that weren't found in cache
-func (c *Client) getAndFilterCachedArtists(ids []string) ([]*internal.SpotifyArtist, []string) {
-	artists := make([]*internal.SpotifyArtist, 0)
+// returns the found artists keyed by id and a slice of remaining filtered ids that weren't found in cache
+func (c *Client) getAndFilterCachedArtists(ids []string) (map[string]*internal.SpotifyArtist, []string) {
+	artists := make(map[string]*internal.SpotifyArtist)
 	remaining := make([]string, 0)
 
 	for _, id := range ids {
+		if _, ok := artists[id]; ok {
+			continue
+		}
+
 		var artist *internal.SpotifyArtist
 		err := c.cache.Get(fmt.Sprintf("Artist-%s", id), &artist)
 
 		if err == nil && artist != nil {
-			artists = append(artists, artist)
-		} else {
+			artists[id] = artist
+		} else if !contains(remaining, id) {
 			remaining = append(remaining, id)
 		}
 	}
@@ -66,6 +80,10 @@ func (c *Client) getAndFilterCachedArtists(ids []string) ([]*internal.SpotifyArt
 
 func (c *Client) cacheArtists(artists []*internal.SpotifyArtist) {
 	for _, artist := range artists {
+		if artist == nil {
+			continue
+		}
+
 		err := c.cache.Set(&internal.CacheItem{
 			Key:   fmt.Sprintf("Artist-%s", artist.ID),
 			Value: &artist,
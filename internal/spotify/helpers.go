@@ -6,20 +6,24 @@ import (
 	"io/ioutil"
 	"log"
 	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
 
 	"github.com/flexicon/spotimoods-go/internal"
 )
 
 // do performs an action against the spotify API and on authorization failure attempts to refresh the given token and try again
 func (c *Client) do(req *http.Request, token *internal.SpotifyToken) (*http.Response, error) {
-	req.Header.Set("Authorization", "Bearer "+token.Token)
+	access, refresh := c.credentials(token)
+	req.Header.Set("Authorization", "Bearer "+access)
 	resp, err := c.http.Do(req)
 	if err != nil {
 		return nil, err
 	}
 	logBodyAndRewind(resp)
 
-	if resp.StatusCode != http.StatusUnauthorized || token.Refresh == "" {
+	if resp.StatusCode != http.StatusUnauthorized || refresh == "" {
 		if resp.StatusCode >= 400 {
 			resp.Body.Close()
 			return nil, httpStatusErr(resp)
@@ -28,11 +32,11 @@ func (c *Client) do(req *http.Request, token *internal.SpotifyToken) (*http.Resp
 	}
 	resp.Body.Close()
 
-	if err := c.Refresh(token); err != nil {
+	if err := c.Refresh(token, access); err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+token.Token)
+	req.Header.Set("Authorization", c.bearer(token))
 	resp, err = c.http.Do(req)
 	if err != nil {
 		return nil, err
@@ -81,6 +85,82 @@ func (c *Client) fetchWithCache(req *http.Request, token *internal.SpotifyToken,
 	return body, nil
 }
 
+// fetchInChunks splits the given ids into chunks of at most size, fetches every chunk concurrently
+// with the url built by buildURL and returns the raw response bodies in the order of the chunks
+func (c *Client) fetchInChunks(token *internal.SpotifyToken, ids []string, size int, buildURL func(chunk []string) string) ([][]byte, error) {
+	count := (len(ids) + size - 1) / size
+	bodies := make([][]byte, count)
+	errs := make([]error, count)
+
+	var wg sync.WaitGroup
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			req, _ := http.NewRequest(http.MethodGet, buildURL(chunkOf(ids, i, size)), nil)
+			bodies[i], errs[i] = c.fetch(req, token)
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return bodies, nil
+}
+
+// bearer builds the authorization header value for the given token, guarding against concurrent refreshes
+func (c *Client) bearer(token *internal.SpotifyToken) string {
+	access, _ := c.credentials(token)
+	return "Bearer " + access
+}
+
+// credentials of the given token, guarding against concurrent refreshes
+func (c *Client) credentials(token *internal.SpotifyToken) (access, refresh string) {
+	c.tokenMu.RLock()
+	defer c.tokenMu.RUnlock()
+
+	return token.Token, token.Refresh
+}
+
+// chunkOf returns the i-th chunk of at most size ids
+func chunkOf(ids []string, i, size int) []string {
+	end := (i + 1) * size
+	if end > len(ids) {
+		end = len(ids)
+	}
+
+	return ids[i*size : end]
+}
+
+// pageQuery prepares the query params for requesting a page with the given options
+func pageQuery(opts internal.SpotifyPageOptions) url.Values {
+	q := url.Values{}
+	if opts.Limit > 0 {
+		q.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if opts.Offset > 0 {
+		q.Set("offset", strconv.Itoa(opts.Offset))
+	}
+	if opts.TimeRange != "" {
+		q.Set("time_range", opts.TimeRange)
+	}
+
+	return q
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
 func httpStatusErr(resp *http.Response) error {
 	body, _ := ioutil.ReadAll(resp.Body)
 	return fmt.Errorf("Http status %d: %s", resp.StatusCode, body)
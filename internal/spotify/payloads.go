@@ -1,8 +1,28 @@
 package spotify
 
+import "github.com/flexicon/spotimoods-go/internal"
+
 // PlaylistPayload for
 // https://developer.spotify.com/documentation/web-api/reference/playlists/create-playlist/
 type PlaylistPayload struct {
-	Name        string `json:"name"`
-	Description string `json:"description,omitempty"`
+	Name          string  `json:"name"`
+	Description   *string `json:"description,omitempty"`
+	Public        *bool   `json:"public,omitempty"`
+	Collaborative *bool   `json:"collaborative,omitempty"`
+}
+
+// newPlaylistPayload with the given details
+func newPlaylistPayload(details internal.PlaylistDetails) PlaylistPayload {
+	return PlaylistPayload{
+		Name:          details.Name,
+		Description:   details.Description,
+		Public:        details.Public,
+		Collaborative: details.Collaborative,
+	}
+}
+
+// PlaylistTracksPayload for
+// https://developer.spotify.com/documentation/web-api/reference/playlists/replace-playlists-tracks/
+type PlaylistTracksPayload struct {
+	URIs []string `json:"uris"`
 }
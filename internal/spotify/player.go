@@ -0,0 +1,76 @@
+package spotify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/flexicon/spotimoods-go/internal"
+	"github.com/pkg/errors"
+)
+
+// GetCurrentlyPlaying track of the user, nil when nothing is playing
+func (c *Client) GetCurrentlyPlaying(token *internal.SpotifyToken) (*internal.SpotifyCurrentlyPlaying, error) {
+	req, _ := http.NewRequest(http.MethodGet, "https://api.spotify.com/v1/me/player/currently-playing?market=from_token", nil)
+
+	resp, err := c.do(req, token)
+	if err != nil {
+		return nil, fmt.Errorf("request failed when getting currently playing track: %v", err)
+	}
+	defer resp.Body.Close()
+
+	// Spotify responds without any content when nothing is playing
+	if resp.StatusCode == http.StatusNoContent {
+		return nil, nil
+	}
+
+	var current internal.SpotifyCurrentlyPlaying
+	if err := json.NewDecoder(resp.Body).Decode(&current); err != nil {
+		return nil, fmt.Errorf("error parsing currently playing response: %v", err)
+	}
+
+	return &current, nil
+}
+
+// GetRecentlyPlayed tracks of the user
+func (c *Client) GetRecentlyPlayed(token *internal.SpotifyToken, opts internal.SpotifyCursorOptions) (*internal.SpotifyPlayHistoryPage, error) {
+	q := url.Values{}
+	if opts.Limit > 0 {
+		q.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	if !opts.After.IsZero() {
+		q.Set("after", strconv.FormatInt(unixMillis(opts.After), 10))
+	}
+	if !opts.Before.IsZero() {
+		q.Set("before", strconv.FormatInt(unixMillis(opts.Before), 10))
+	}
+
+	recentURL, _ := url.Parse("https://api.spotify.com/v1/me/player/recently-played")
+	recentURL.RawQuery = q.Encode()
+
+	req, _ := http.NewRequest(http.MethodGet, recentURL.String(), nil)
+	cacheItem := &internal.CacheItem{
+		Key: fmt.Sprintf("GetRecentlyPlayed-user-%d-%s", token.UserID, recentURL.RawQuery),
+		TTL: time.Minute,
+	}
+
+	body, err := c.fetchWithCache(req, token, cacheItem)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to retrieve recently played tracks")
+	}
+
+	var recentResponse internal.SpotifyPlayHistoryPage
+	if err := json.NewDecoder(bytes.NewBuffer(body)).Decode(&recentResponse); err != nil {
+		return nil, fmt.Errorf("error parsing recently played response: %v", err)
+	}
+
+	return &recentResponse, nil
+}
+
+func unixMillis(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
@@ -0,0 +1,107 @@
+package spotify
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/flexicon/spotimoods-go/internal"
+	"github.com/pkg/errors"
+)
+
+// playlistFields limits playlist responses to their details, leaving out the first page of items
+const playlistFields = "id,name,description,uri,href,collaborative,public,snapshot_id,images,owner(id,display_name),tracks(total),external_urls"
+
+// GetPlaylist retrieves the details of the given playlist
+func (c *Client) GetPlaylist(token *internal.SpotifyToken, id string) (*internal.SpotifyPlaylist, error) {
+	playlistURL := fmt.Sprintf("https://api.spotify.com/v1/playlists/%s?fields=%s", id, url.QueryEscape(playlistFields))
+	req, _ := http.NewRequest(http.MethodGet, playlistURL, nil)
+
+	body, err := c.fetch(req, token)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to retrieve playlist")
+	}
+
+	var playlist internal.SpotifyPlaylist
+	if err := json.NewDecoder(bytes.NewBuffer(body)).Decode(&playlist); err != nil {
+		return nil, fmt.Errorf("error parsing playlist response: %v", err)
+	}
+
+	return &playlist, nil
+}
+
+// IsFollowingPlaylist checks whether the user of the given token still follows the given playlist
+func (c *Client) IsFollowingPlaylist(token *internal.SpotifyToken, id string) (bool, error) {
+	followURL := fmt.Sprintf("https://api.spotify.com/v1/playlists/%s/followers/contains?ids=%s", id, url.QueryEscape(token.User.SpotifyID))
+	req, _ := http.NewRequest(http.MethodGet, followURL, nil)
+
+	body, err := c.fetch(req, token)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to check playlist followers")
+	}
+
+	// Spotify responds with a flag for every requested user
+	var following []bool
+	if err := json.NewDecoder(bytes.NewBuffer(body)).Decode(&following); err != nil {
+		return false, fmt.Errorf("error parsing playlist followers response: %v", err)
+	}
+
+	return len(following) > 0 && following[0], nil
+}
+
+// FollowPlaylist for the user of the given token
+func (c *Client) FollowPlaylist(token *internal.SpotifyToken, id string) error {
+	req, _ := http.NewRequest(http.MethodPut, fmt.Sprintf("https://api.spotify.com/v1/playlists/%s/followers", id), nil)
+
+	resp, err := c.do(req, token)
+	if err != nil {
+		return fmt.Errorf("request failed when following playlist: %v", err)
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+// UploadPlaylistCover replaces the cover image of the given playlist with the given JPEG
+func (c *Client) UploadPlaylistCover(token *internal.SpotifyToken, id string, jpeg []byte) error {
+	body := base64.StdEncoding.EncodeToString(jpeg)
+	req, err := http.NewRequest(http.MethodPut, fmt.Sprintf("https://api.spotify.com/v1/playlists/%s/images", id), strings.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to prepare request: %v", err)
+	}
+	req.Header.Set("Content-Type", "image/jpeg")
+
+	resp, err := c.do(req, token)
+	if err != nil {
+		return fmt.Errorf("request failed when uploading playlist cover: %v", err)
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+// GetPlaylistItems retrieves a page of the items of the given playlist
+func (c *Client) GetPlaylistItems(token *internal.SpotifyToken, id string, opts internal.SpotifyPageOptions) (*internal.SpotifyPlaylistItemPage, error) {
+	itemsURL, _ := url.Parse(fmt.Sprintf("https://api.spotify.com/v1/playlists/%s/tracks", id))
+	q := pageQuery(opts)
+	q.Set("market", "from_token")
+	itemsURL.RawQuery = q.Encode()
+
+	// Playlist contents are not cached, as they change with every refresh of the mood
+	req, _ := http.NewRequest(http.MethodGet, itemsURL.String(), nil)
+	body, err := c.fetch(req, token)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to retrieve playlist items")
+	}
+
+	var page internal.SpotifyPlaylistItemPage
+	if err := json.NewDecoder(bytes.NewBuffer(body)).Decode(&page); err != nil {
+		return nil, fmt.Errorf("error parsing playlist items response: %v", err)
+	}
+
+	return &page, nil
+}
@@ -0,0 +1,73 @@
+package spotify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/flexicon/spotimoods-go/internal"
+	"github.com/pkg/errors"
+)
+
+// GetGenreSeeds lists the genres available for recommendations
+func (c *Client) GetGenreSeeds(token *internal.SpotifyToken) ([]string, error) {
+	req, _ := http.NewRequest(http.MethodGet, "https://api.spotify.com/v1/recommendations/available-genre-seeds", nil)
+	cacheItem := &internal.CacheItem{
+		Key: "GetGenreSeeds",
+		TTL: time.Hour * 24,
+	}
+
+	body, err := c.fetchWithCache(req, token, cacheItem)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to retrieve genre seeds")
+	}
+
+	var seedsResponse struct {
+		Genres []string `json:"genres"`
+	}
+	if err := json.NewDecoder(bytes.NewBuffer(body)).Decode(&seedsResponse); err != nil {
+		return nil, fmt.Errorf("error parsing genre seeds response: %v", err)
+	}
+
+	return seedsResponse.Genres, nil
+}
+
+// GetRecommendations of tracks based on the given seeds
+func (c *Client) GetRecommendations(token *internal.SpotifyToken, seeds internal.SpotifyRecommendationSeeds, limit int) ([]*internal.SpotifyTrack, error) {
+	q := url.Values{}
+	q.Set("limit", strconv.Itoa(limit))
+	q.Set("market", "from_token")
+	if len(seeds.Artists) > 0 {
+		q.Set("seed_artists", strings.Join(seeds.Artists, ","))
+	}
+	if len(seeds.Tracks) > 0 {
+		q.Set("seed_tracks", strings.Join(seeds.Tracks, ","))
+	}
+	if len(seeds.Genres) > 0 {
+		q.Set("seed_genres", strings.Join(seeds.Genres, ","))
+	}
+
+	recommendationsURL, _ := url.Parse("https://api.spotify.com/v1/recommendations")
+	recommendationsURL.RawQuery = q.Encode()
+
+	req, _ := http.NewRequest(http.MethodGet, recommendationsURL.String(), nil)
+	body, err := c.fetch(req, token)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to retrieve recommendations")
+	}
+
+	var recommendationsResponse struct {
+		Tracks []*internal.SpotifyTrack `json:"tracks"`
+	}
+	if err := json.NewDecoder(bytes.NewBuffer(body)).Decode(&recommendationsResponse); err != nil {
+		return nil, fmt.Errorf("error parsing recommendations response: %v", err)
+	}
+	c.cacheTracks(recommendationsResponse.Tracks)
+
+	return recommendationsResponse.Tracks, nil
+}
@@ -0,0 +1,91 @@
+package spotify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/flexicon/spotimoods-go/internal"
+	"github.com/pkg/errors"
+)
+
+// Search for any combination of the given search types by the given query
+func (c *Client) Search(token *internal.SpotifyToken, query string, types []string, opts internal.SpotifyPageOptions) (*internal.SpotifySearchResult, error) {
+	searchURL, _ := url.Parse("https://api.spotify.com/v1/search")
+	q := pageQuery(opts)
+	q.Add("q", query)
+	q.Add("type", strings.Join(types, ","))
+	searchURL.RawQuery = q.Encode()
+
+	req, _ := http.NewRequest(http.MethodGet, searchURL.String(), nil)
+	cacheItem := &internal.CacheItem{
+		Key: fmt.Sprintf("Search-user-%d-%s", token.UserID, searchURL.RawQuery),
+		TTL: time.Minute,
+	}
+
+	body, err := c.fetchWithCache(req, token, cacheItem)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to retrieve search results")
+	}
+
+	var result internal.SpotifySearchResult
+	if err := json.NewDecoder(bytes.NewBuffer(body)).Decode(&result); err != nil {
+		return nil, fmt.Errorf("error parsing search response: %v", err)
+	}
+
+	// Store every found item on its own, so later lookups by ID can skip the request
+	if result.Artists != nil {
+		c.cacheArtists(result.Artists.Items)
+	}
+	if result.Tracks != nil {
+		c.cacheTracks(result.Tracks.Items)
+	}
+	if result.Albums != nil {
+		c.cacheAlbums(result.Albums.Items)
+	}
+
+	return &result, nil
+}
+
+// SearchForArtists by the given query
+func (c *Client) SearchForArtists(token *internal.SpotifyToken, query string, opts internal.SpotifyPageOptions) (*internal.SpotifyArtistPage, error) {
+	result, err := c.Search(token, query, []string{internal.SearchTypeArtist}, opts)
+	if err != nil {
+		return nil, err
+	}
+	if result.Artists == nil {
+		return &internal.SpotifyArtistPage{}, nil
+	}
+
+	return result.Artists, nil
+}
+
+// SearchForTracks by the given query
+func (c *Client) SearchForTracks(token *internal.SpotifyToken, query string, opts internal.SpotifyPageOptions) (*internal.SpotifyTrackPage, error) {
+	result, err := c.Search(token, query, []string{internal.SearchTypeTrack}, opts)
+	if err != nil {
+		return nil, err
+	}
+	if result.Tracks == nil {
+		return &internal.SpotifyTrackPage{}, nil
+	}
+
+	return result.Tracks, nil
+}
+
+// SearchForAlbums by the given query
+func (c *Client) SearchForAlbums(token *internal.SpotifyToken, query string, opts internal.SpotifyPageOptions) (*internal.SpotifyAlbumPage, error) {
+	result, err := c.Search(token, query, []string{internal.SearchTypeAlbum}, opts)
+	if err != nil {
+		return nil, err
+	}
+	if result.Albums == nil {
+		return &internal.SpotifyAlbumPage{}, nil
+	}
+
+	return result.Albums, nil
+}
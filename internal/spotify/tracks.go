@@ -0,0 +1,170 @@
+package spotify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/flexicon/spotimoods-go/internal"
+	"github.com/pkg/errors"
+)
+
+// GetTopTracks for the user
+func (c *Client) GetTopTracks(token *internal.SpotifyToken, opts internal.SpotifyPageOptions) (*internal.SpotifyTrackPage, error) {
+	topURL, _ := url.Parse("https://api.spotify.com/v1/me/top/tracks")
+	topURL.RawQuery = pageQuery(opts).Encode()
+
+	req, _ := http.NewRequest(http.MethodGet, topURL.String(), nil)
+	cacheItem := &internal.CacheItem{
+		Key: fmt.Sprintf("GetTopTracks-user-%d-%s", token.UserID, topURL.RawQuery),
+		TTL: time.Minute,
+	}
+
+	body, err := c.fetchWithCache(req, token, cacheItem)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to retrieve top tracks")
+	}
+
+	var topResponse internal.SpotifyTrackPage
+	if err := json.NewDecoder(bytes.NewBuffer(body)).Decode(&topResponse); err != nil {
+		return nil, fmt.Errorf("error parsing top tracks response: %v", err)
+	}
+	c.cacheTracks(topResponse.Items)
+
+	return &topResponse, nil
+}
+
+// maxTrackIDs is the amount of IDs the several tracks endpoint accepts in a single request
+const maxTrackIDs = 50
+
+// GetTracksByIDs retrieves the tracks related to the given IDs, keyed by their ID.
+// IDs for which Spotify returned no track are reported in the second return value.
+func (c *Client) GetTracksByIDs(token *internal.SpotifyToken, ids []string) (map[string]*internal.SpotifyTrack, []string, error) {
+	// First check cache for tracks and only make requests if any non-cached tracks remain
+	tracks, remainingIDs := c.getAndFilterCachedTracks(ids)
+	if len(remainingIDs) == 0 {
+		return tracks, nil, nil
+	}
+
+	bodies, err := c.fetchInChunks(token, remainingIDs, maxTrackIDs, func(chunk []string) string {
+		return fmt.Sprintf("https://api.spotify.com/v1/tracks?ids=%s", strings.Join(chunk, ","))
+	})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to retrieve tracks by ids")
+	}
+
+	missing := make([]string, 0)
+	for i, body := range bodies {
+		var response struct {
+			Tracks []*internal.SpotifyTrack `json:"tracks"`
+		}
+		if err := json.NewDecoder(bytes.NewBuffer(body)).Decode(&response); err != nil {
+			return nil, nil, fmt.Errorf("error parsing tracks response: %v", err)
+		}
+
+		// Spotify responds with tracks in the order of the requested IDs, using null for unknown ones
+		for j, id := range chunkOf(remainingIDs, i, maxTrackIDs) {
+			if j >= len(response.Tracks) || response.Tracks[j] == nil {
+				missing = append(missing, id)
+				continue
+			}
+			tracks[id] = response.Tracks[j]
+		}
+		c.cacheTracks(response.Tracks)
+	}
+
+	return tracks, missing, nil
+}
+
+// GetArtistTopTracks retrieves the most popular tracks of the given artist in the user's market
+func (c *Client) GetArtistTopTracks(token *internal.SpotifyToken, artistID string) ([]*internal.SpotifyTrack, error) {
+	topURL := fmt.Sprintf("https://api.spotify.com/v1/artists/%s/top-tracks?market=from_token", artistID)
+	req, _ := http.NewRequest(http.MethodGet, topURL, nil)
+	cacheItem := &internal.CacheItem{
+		Key: fmt.Sprintf("GetArtistTopTracks-user-%d-%s", token.UserID, artistID),
+		TTL: time.Minute * 15,
+	}
+
+	body, err := c.fetchWithCache(req, token, cacheItem)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to retrieve artist top tracks")
+	}
+
+	var topResponse struct {
+		Tracks []*internal.SpotifyTrack `json:"tracks"`
+	}
+	if err := json.NewDecoder(bytes.NewBuffer(body)).Decode(&topResponse); err != nil {
+		return nil, fmt.Errorf("error parsing artist top tracks response: %v", err)
+	}
+	c.cacheTracks(topResponse.Tracks)
+
+	return topResponse.Tracks, nil
+}
+
+// GetAudioFeatures of the given track
+func (c *Client) GetAudioFeatures(token *internal.SpotifyToken, trackID string) (*internal.SpotifyAudioFeatures, error) {
+	req, _ := http.NewRequest(http.MethodGet, fmt.Sprintf("https://api.spotify.com/v1/audio-features/%s", trackID), nil)
+	cacheItem := &internal.CacheItem{
+		Key: fmt.Sprintf("GetAudioFeatures-%s", trackID),
+		TTL: time.Hour * 24,
+	}
+
+	body, err := c.fetchWithCache(req, token, cacheItem)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to retrieve audio features")
+	}
+
+	var features internal.SpotifyAudioFeatures
+	if err := json.NewDecoder(bytes.NewBuffer(body)).Decode(&features); err != nil {
+		return nil, fmt.Errorf("error parsing audio features response: %v", err)
+	}
+
+	return &features, nil
+}
+
+// getAndFilterCachedTracks tries to retrieve each track from cache by id,
+// returns the found tracks keyed by id and a slice of remaining filtered ids that weren't found in cache
+func (c *Client) getAndFilterCachedTracks(ids []string) (map[string]*internal.SpotifyTrack, []string) {
+	tracks := make(map[string]*internal.SpotifyTrack)
+	remaining := make([]string, 0)
+
+	for _, id := range ids {
+		if _, ok := tracks[id]; ok {
+			continue
+		}
+
+		var track *internal.SpotifyTrack
+		err := c.cache.Get(fmt.Sprintf("Track-%s", id), &track)
+
+		if err == nil && track != nil {
+			tracks[id] = track
+		} else if !contains(remaining, id) {
+			remaining = append(remaining, id)
+		}
+	}
+
+	return tracks, remaining
+}
+
+func (c *Client) cacheTracks(tracks []*internal.SpotifyTrack) {
+	for _, track := range tracks {
+		if track == nil {
+			continue
+		}
+
+		err := c.cache.Set(&internal.CacheItem{
+			Key:   fmt.Sprintf("Track-%s", track.ID),
+			Value: &track,
+			TTL:   time.Minute * 15,
+		})
+
+		if err != nil {
+			log.Println(errors.Wrap(err, "failed to store track in cache"))
+		}
+	}
+}
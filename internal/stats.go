@@ -0,0 +1,179 @@
+package internal
+
+import (
+	"fmt"
+	"time"
+)
+
+// statsCacheTTL for how long computed stats are served before being recalculated
+const statsCacheTTL = 5 * time.Minute
+
+// MoodStats insights of a single mood
+type MoodStats struct {
+	MoodID     uint           `json:"mood_id"`
+	TagCounts  map[string]int `json:"tag_counts"`
+	Genres     []GenreCount   `json:"genres"`
+	TrackCount int            `json:"track_count"`
+	DurationMs int            `json:"duration_ms"`
+	SyncedAt   *time.Time     `json:"synced_at"`
+	// Plays of the mood's tracks, only set when the user has any listening history
+	Plays *int `json:"plays,omitempty"`
+}
+
+// UserStats insights across all moods of a user
+type UserStats struct {
+	MoodCount  int            `json:"mood_count"`
+	TagCounts  map[string]int `json:"tag_counts"`
+	Genres     []GenreCount   `json:"genres"`
+	TrackCount int            `json:"track_count"`
+	DurationMs int            `json:"duration_ms"`
+	Plays      *int           `json:"plays,omitempty"`
+	Moods      []*MoodStats   `json:"moods"`
+}
+
+// StatsService for computing mood insights
+type StatsService struct {
+	moods   MoodRepository
+	plays   PlayRepository
+	spotify SpotifyClient
+	cache   Cache
+}
+
+// NewStatsService constructor
+func NewStatsService(moods MoodRepository, plays PlayRepository, s SpotifyClient, c Cache) *StatsService {
+	return &StatsService{
+		moods:   moods,
+		plays:   plays,
+		spotify: s,
+		cache:   c,
+	}
+}
+
+// GetForMood returns the stats of the given mood
+func (s *StatsService) GetForMood(mood *Mood, token *SpotifyToken) (*MoodStats, error) {
+	var stats MoodStats
+	err := s.cache.Once(&CacheItem{
+		Key:   fmt.Sprintf("MoodStats-%d", mood.ID),
+		Value: &stats,
+		TTL:   statsCacheTTL,
+		Do: func(*CacheItem) (interface{}, error) {
+			all, err := s.compute([]*Mood{mood}, token)
+			if err != nil {
+				return nil, err
+			}
+			return all.Moods[0], nil
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &stats, nil
+}
+
+// GetForUser returns the stats across all moods of the given user
+func (s *StatsService) GetForUser(token *SpotifyToken) (*UserStats, error) {
+	var stats UserStats
+	err := s.cache.Once(&CacheItem{
+		Key:   fmt.Sprintf("MoodStats-user-%d", token.UserID),
+		Value: &stats,
+		TTL:   statsCacheTTL,
+		Do: func(*CacheItem) (interface{}, error) {
+			moods, err := s.moods.FindByUser(&token.User)
+			if err != nil {
+				return nil, err
+			}
+			return s.compute(moods, token)
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &stats, nil
+}
+
+// compute the stats of every given mood along with their totals
+func (s *StatsService) compute(moods []*Mood, token *SpotifyToken) (*UserStats, error) {
+	stats := &UserStats{
+		MoodCount: len(moods),
+		TagCounts: make(map[string]int),
+		Genres:    []GenreCount{},
+		Moods:     make([]*MoodStats, 0, len(moods)),
+	}
+	if len(moods) == 0 {
+		return stats, nil
+	}
+
+	ids := make([]uint, 0, len(moods))
+	var artistIDs []string
+	for _, mood := range moods {
+		ids = append(ids, mood.ID)
+		for _, id := range IDsByType(mood.Tags, TagTypeArtist) {
+			if !containsString(artistIDs, id) {
+				artistIDs = append(artistIDs, id)
+			}
+		}
+	}
+
+	tagCounts, err := s.moods.CountTags(ids)
+	if err != nil {
+		return nil, err
+	}
+	artists, _, err := s.spotify.GetArtistsByIDs(token, artistIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Plays are only reported once there is any history to count them from
+	latest, err := s.plays.LatestPlayedAt(&token.User)
+	if err != nil {
+		return nil, err
+	}
+	var playCounts map[uint]int
+	if !latest.IsZero() {
+		if playCounts, err = s.plays.CountTotalByMoods(ids); err != nil {
+			return nil, err
+		}
+		total := 0
+		stats.Plays = &total
+	}
+
+	stats.Genres = countGenres(artists)
+	for _, mood := range moods {
+		moodStats := &MoodStats{
+			MoodID:     mood.ID,
+			TagCounts:  make(map[string]int),
+			Genres:     countGenres(moodArtists(mood, artists)),
+			TrackCount: mood.TrackCount,
+			DurationMs: mood.DurationMs,
+			SyncedAt:   mood.SyncedAt,
+		}
+		for tagType, count := range tagCounts[mood.ID] {
+			moodStats.TagCounts[tagType] = count
+			stats.TagCounts[tagType] += count
+		}
+		if playCounts != nil {
+			plays := playCounts[mood.ID]
+			moodStats.Plays = &plays
+			*stats.Plays += plays
+		}
+
+		stats.TrackCount += mood.TrackCount
+		stats.DurationMs += mood.DurationMs
+		stats.Moods = append(stats.Moods, moodStats)
+	}
+
+	return stats, nil
+}
+
+// moodArtists picks the tagged artists of the given mood out of the given lookup
+func moodArtists(mood *Mood, artists map[string]*SpotifyArtist) map[string]*SpotifyArtist {
+	picked := make(map[string]*SpotifyArtist)
+	for _, id := range IDsByType(mood.Tags, TagTypeArtist) {
+		if artist, ok := artists[id]; ok {
+			picked[id] = artist
+		}
+	}
+	return picked
+}
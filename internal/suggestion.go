@@ -0,0 +1,210 @@
+package internal
+
+import (
+	"sort"
+	"strings"
+)
+
+const (
+	minSuggestions = 3
+	maxSuggestions = 6
+	// maxSuggestionArtists caps how many artists are tagged in a single suggested mood
+	maxSuggestionArtists = 10
+)
+
+// suggestionColors are handed out to suggested moods in order
+var suggestionColors = []string{"#1db954", "#e91e63", "#3f51b5", "#ff9800", "#009688", "#9c27b0"}
+
+// MoodSuggestion proposed from the user's listening
+type MoodSuggestion struct {
+	Name   string   `json:"name"`
+	Color  string   `json:"color"`
+	Genres []string `json:"genres"`
+	Tags   []Tag    `json:"tags"`
+}
+
+// SuggestMoods proposes moods by grouping the user's top and recently played artists by their shared genres
+func (s *MoodService) SuggestMoods(token *SpotifyToken) ([]*MoodSuggestion, error) {
+	artists, err := s.listenedArtists(token)
+	if err != nil {
+		return nil, err
+	}
+
+	return suggestMoods(artists), nil
+}
+
+// listenedArtists collects the user's top artists followed by any other recently played artists
+func (s *MoodService) listenedArtists(token *SpotifyToken) ([]*SpotifyArtist, error) {
+	top, err := s.spotify.GetTopArtists(token, SpotifyPageOptions{Limit: 50})
+	if err != nil {
+		return nil, err
+	}
+
+	recent, err := s.spotify.GetRecentlyPlayed(token, SpotifyCursorOptions{Limit: 50})
+	if err != nil {
+		return nil, err
+	}
+
+	artists := make([]*SpotifyArtist, 0)
+	seen := make(map[string]bool)
+	for _, artist := range top.Items {
+		if !seen[artist.ID] {
+			seen[artist.ID] = true
+			artists = append(artists, artist)
+		}
+	}
+
+	// Recently played tracks only hold simplified artists, without any genres
+	recentIDs := make([]string, 0)
+	for _, play := range recent.Items {
+		for _, artist := range play.Track.Artists {
+			if !seen[artist.ID] {
+				seen[artist.ID] = true
+				recentIDs = append(recentIDs, artist.ID)
+			}
+		}
+	}
+
+	recentArtists, _, err := s.spotify.GetArtistsByIDs(token, recentIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range recentIDs {
+		if artist, ok := recentArtists[id]; ok {
+			artists = append(artists, artist)
+		}
+	}
+
+	return artists, nil
+}
+
+// suggestMoods clusters the given artists, ordered by relevance, by genre overlap.
+// Every cluster starts from the most common genre among the remaining artists and takes in all artists sharing it,
+// along with artists sharing at least two of the genres common to that group.
+// Smaller clusters are only allowed when there aren't enough suggestions otherwise.
+func suggestMoods(artists []*SpotifyArtist) []*MoodSuggestion {
+	assigned := make(map[string]bool)
+	suggestions := make([]*MoodSuggestion, 0)
+
+	for _, minSize := range []int{2, 1} {
+		for len(suggestions) < maxSuggestions {
+			genre := mostCommonGenre(artists, assigned)
+			if genre == "" {
+				break
+			}
+
+			cluster := clusterByGenre(artists, assigned, genre)
+			if len(cluster) < minSize {
+				// Not enough artists share even the most common genre, so no other genre will do either
+				break
+			}
+
+			for _, artist := range cluster {
+				assigned[artist.ID] = true
+			}
+			suggestions = append(suggestions, newSuggestion(genre, cluster, len(suggestions)))
+		}
+
+		if len(suggestions) >= minSuggestions {
+			break
+		}
+	}
+
+	return suggestions
+}
+
+// mostCommonGenre among the artists which aren't assigned to a cluster yet
+func mostCommonGenre(artists []*SpotifyArtist, assigned map[string]bool) string {
+	counts := make(map[string]int)
+	for _, artist := range artists {
+		if assigned[artist.ID] {
+			continue
+		}
+		for _, genre := range artist.Genres {
+			counts[genre]++
+		}
+	}
+
+	best := ""
+	for genre, count := range counts {
+		if count > counts[best] || (count == counts[best] && genre < best) {
+			best = genre
+		}
+	}
+
+	return best
+}
+
+// clusterByGenre gathers the unassigned artists of the given genre and artists closely overlapping with them
+func clusterByGenre(artists []*SpotifyArtist, assigned map[string]bool, genre string) []*SpotifyArtist {
+	cluster := make([]*SpotifyArtist, 0)
+	inCluster := make(map[string]bool)
+	counts := make(map[string]int)
+
+	for _, artist := range artists {
+		if !assigned[artist.ID] && containsString(artist.Genres, genre) {
+			cluster = append(cluster, artist)
+			inCluster[artist.ID] = true
+			for _, g := range artist.Genres {
+				counts[g]++
+			}
+		}
+	}
+
+	// Genres shared by at least half of the cluster describe it
+	profile := make([]string, 0)
+	for g, count := range counts {
+		if count*2 >= len(cluster) {
+			profile = append(profile, g)
+		}
+	}
+
+	for _, artist := range artists {
+		if assigned[artist.ID] || inCluster[artist.ID] {
+			continue
+		}
+
+		overlap := 0
+		for _, g := range artist.Genres {
+			if containsString(profile, g) {
+				overlap++
+			}
+		}
+		if overlap >= 2 {
+			cluster = append(cluster, artist)
+			inCluster[artist.ID] = true
+		}
+	}
+
+	return cluster
+}
+
+func newSuggestion(genre string, cluster []*SpotifyArtist, index int) *MoodSuggestion {
+	suggestion := &MoodSuggestion{
+		Name:   strings.Title(genre),
+		Color:  suggestionColors[index%len(suggestionColors)],
+		Genres: make([]string, 0),
+		Tags:   make([]Tag, 0),
+	}
+
+	genres := make(map[string]bool)
+	for i, artist := range cluster {
+		for _, g := range artist.Genres {
+			genres[g] = true
+		}
+		if i < maxSuggestionArtists {
+			suggestion.Tags = append(suggestion.Tags, Tag{
+				Type:       TagTypeArtist,
+				SpotifyID:  artist.ID,
+				ArtistData: artist,
+			})
+		}
+	}
+
+	for g := range genres {
+		suggestion.Genres = append(suggestion.Genres, g)
+	}
+	sort.Strings(suggestion.Genres)
+
+	return suggestion
+}
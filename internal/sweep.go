@@ -0,0 +1,52 @@
+package internal
+
+import "time"
+
+// SweepReport of the moods a sweep fixed
+type SweepReport struct {
+	// Requeued moods which were still without a playlist
+	Requeued []uint
+	// Deleted moods past their undo window, whose playlist is still to be unfollowed
+	Deleted []uint
+}
+
+// Sweep queues the playlist jobs again for moods they were lost for, once the given time has passed since.
+// Moods are left alone as long as a job may still be pending for them.
+// Playlists of deleted moods are only queued for deletion once the given undo window has passed.
+func (s *MoodService) Sweep(after, undoWindow time.Duration) (*SweepReport, error) {
+	now := time.Now()
+	before := now.Add(-after)
+	report := &SweepReport{Requeued: make([]uint, 0), Deleted: make([]uint, 0)}
+
+	stuck, err := s.r.FindWithoutPlaylist(before)
+	if err != nil {
+		return nil, err
+	}
+	for _, mood := range stuck {
+		if err := s.q.AddPlaylist(mood); err != nil {
+			return report, err
+		}
+		if err := s.r.MarkPlaylistQueued(mood, now); err != nil {
+			return report, err
+		}
+		report.Requeued = append(report.Requeued, mood.ID)
+	}
+
+	undeleted, err := s.r.FindUndeletedPlaylists(now.Add(-undoWindow))
+	if err != nil {
+		return report, err
+	}
+	for _, mood := range undeleted {
+		if err := s.q.DeletePlaylist(mood); err != nil {
+			return report, err
+		}
+		report.Deleted = append(report.Deleted, mood.ID)
+	}
+
+	return report, nil
+}
+
+// MarkPlaylistDeleted for the mood by the given ID, once its playlist was unfollowed
+func (s *MoodService) MarkPlaylistDeleted(moodID uint) error {
+	return s.r.MarkPlaylistDeleted(moodID, time.Now())
+}
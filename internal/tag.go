@@ -1,8 +1,37 @@
 package internal
 
-// Tag linking an artist to a mood
+// Tag types, referring to the kind of spotify item a tag links to
+const (
+	TagTypeArtist = "artist"
+	TagTypeTrack  = "track"
+	TagTypeAlbum  = "album"
+	TagTypeGenre  = "genre"
+)
+
+// Tag linking a spotify artist, track, album or genre to a mood
 type Tag struct {
-	ArtistID   string        `gorm:"primary_key;auto_increment:false" json:"artist_id"`
-	MoodID     uint          `gorm:"primary_key;auto_increment:false" json:"mood_id"`
-	ArtistData SpotifyArtist `gorm:"-" json:"artist_data,omitempty"`
+	MoodID    uint   `gorm:"primary_key;auto_increment:false" json:"mood_id"`
+	Type      string `gorm:"primary_key;size:16" json:"type"`
+	SpotifyID string `gorm:"primary_key" json:"spotify_id"`
+
+	ArtistData *SpotifyArtist `gorm:"-" json:"artist_data,omitempty"`
+	TrackData  *SpotifyTrack  `gorm:"-" json:"track_data,omitempty"`
+	AlbumData  *SpotifyAlbum  `gorm:"-" json:"album_data,omitempty"`
+}
+
+// TableName of typed tags, which replaced the artist only tags table
+func (Tag) TableName() string {
+	return "mood_tags"
+}
+
+// IDsByType collects the spotify IDs of the given tags which are of the given type
+func IDsByType(tags []Tag, tagType string) []string {
+	ids := make([]string, 0)
+	for _, tag := range tags {
+		if tag.Type == tagType {
+			ids = append(ids, tag.SpotifyID)
+		}
+	}
+
+	return ids
 }
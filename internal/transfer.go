@@ -0,0 +1,122 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// artistMatchLimit of search results checked for an artist with exactly the imported name
+const artistMatchLimit = 5
+
+// MoodImport of a single mood, along with the names of artists to tag which are still to be resolved
+type MoodImport struct {
+	// Row of the import the mood was read from
+	Row         int
+	Mood        *Mood
+	ArtistNames []string
+}
+
+// ImportError of a single row of an import
+type ImportError struct {
+	Row int    `json:"row"`
+	Msg string `json:"message"`
+}
+
+// ExportForUser gathers all moods of the given user along with the tagged artists, keyed by ID
+func (s *MoodService) ExportForUser(token *SpotifyToken) ([]*Mood, map[string]*SpotifyArtist, error) {
+	moods, err := s.r.FindByUser(&token.User)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var ids []string
+	for _, mood := range moods {
+		ids = append(ids, IDsByType(mood.Tags, TagTypeArtist)...)
+	}
+
+	artists, _, err := s.spotify.GetArtistsByIDs(token, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return moods, artists, nil
+}
+
+// ImportForUser resolves artist names of the given imports and saves all of them as new moods of the given user.
+// Nothing is saved when any row fails, in which case the errors of every failed row are returned instead.
+func (s *MoodService) ImportForUser(imports []MoodImport, token *SpotifyToken) ([]*Mood, []ImportError, error) {
+	resolved := make(map[string]string)
+	var importErrs []ImportError
+
+	position, err := s.r.NextPosition(&token.User)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	moods := make([]*Mood, 0, len(imports))
+	for _, imp := range imports {
+		for _, name := range imp.ArtistNames {
+			key := strings.ToLower(name)
+			if _, ok := resolved[key]; !ok {
+				id, err := s.resolveArtist(name, token)
+				if err != nil {
+					return nil, nil, err
+				}
+				resolved[key] = id
+			}
+
+			if resolved[key] == "" {
+				importErrs = append(importErrs, ImportError{Row: imp.Row, Msg: fmt.Sprintf("artist %q not found", name)})
+				continue
+			}
+			if !containsString(IDsByType(imp.Mood.Tags, TagTypeArtist), resolved[key]) {
+				imp.Mood.Tags = append(imp.Mood.Tags, Tag{Type: TagTypeArtist, SpotifyID: resolved[key]})
+			}
+		}
+
+		imp.Mood.User = token.User
+		imp.Mood.Position = position
+		imp.Mood.PlaylistQueuedAt = &now
+		position++
+		moods = append(moods, imp.Mood)
+	}
+	if len(importErrs) > 0 {
+		return nil, importErrs, nil
+	}
+
+	if err := s.r.SaveAll(moods); err != nil {
+		return nil, nil, err
+	}
+
+	// Add tasks to create playlists in spotify
+	for _, mood := range moods {
+		s.record(mood, AuditActionCreate, AuditSourceAPI, &token.User, diffAudit(auditState{}, mood.auditState()))
+		if err := s.q.AddPlaylist(mood); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return moods, nil, nil
+}
+
+// resolveArtist finds the ID of an artist by name, preferring an exact match over the most relevant result.
+// An empty ID is returned when no artist was found.
+func (s *MoodService) resolveArtist(name string, token *SpotifyToken) (string, error) {
+	page, err := s.spotify.SearchForArtists(token, name, SpotifyPageOptions{Limit: artistMatchLimit})
+	if err != nil {
+		return "", err
+	}
+	if len(page.Items) == 0 {
+		return "", nil
+	}
+
+	for _, artist := range page.Items {
+		if strings.EqualFold(artist.Name, name) {
+			return artist.ID, nil
+		}
+	}
+
+	return page.Items[0].ID, nil
+}
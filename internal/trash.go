@@ -0,0 +1,42 @@
+package internal
+
+import "time"
+
+// GetTrashForUser lists the moods of the given user which are deleted, but can still be restored within the undo window
+func (s *MoodService) GetTrashForUser(user *User, undoWindow time.Duration) ([]*Mood, error) {
+	return s.r.FindDeletedByUser(user, time.Now().Add(-undoWindow))
+}
+
+// RestoreForUser the deleted mood by the given ID and user, as long as it is still within the undo window
+func (s *MoodService) RestoreForUser(id uint, user *User, undoWindow time.Duration) (*Mood, error) {
+	mood, err := s.r.Restore(id, user, time.Now().Add(-undoWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	// The position the mood had may have been given to another one by reordering meanwhile, so it goes last
+	if mood.Position, err = s.r.NextPosition(user); err != nil {
+		return nil, err
+	}
+	if err := s.r.Update(mood, "Position"); err != nil {
+		return nil, err
+	}
+
+	// Moods deleted before the undo window existed may have lost their playlist already, so a new one is created
+	if mood.PlaylistDeletedAt != nil {
+		now := time.Now()
+		mood.PlaylistID = ""
+		mood.PlaylistDeletedAt = nil
+		mood.PlaylistQueuedAt = &now
+		if err := s.r.Update(mood, "PlaylistID", "PlaylistDeletedAt", "PlaylistQueuedAt"); err != nil {
+			return nil, err
+		}
+		if err := s.q.AddPlaylist(mood); err != nil {
+			return nil, err
+		}
+	}
+
+	s.record(mood, AuditActionRestore, AuditSourceAPI, user, diffAudit(auditState{}, mood.auditState()))
+
+	return mood, nil
+}
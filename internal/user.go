@@ -1,6 +1,8 @@
 package internal
 
 import (
+	"time"
+
 	"github.com/jinzhu/gorm"
 )
 
@@ -11,12 +13,16 @@ type User struct {
 	DisplayName string
 	Image       string `gorm:"size:500"`
 	SpotifyID   string `gorm:"not null"`
+	// DriftPolicy decides how moods are reconciled with playlists changed directly in spotify
+	DriftPolicy string `gorm:"size:16;not null;default:'flag'"`
 }
 
 // UserRepository for interacting with user data
 type UserRepository interface {
 	// FindByEmail checks for an existing active user by a given email
 	FindByEmail(email string) (*User, error)
+	// FindActive users, whose spotify token was refreshed since the given time
+	FindActive(since time.Time) ([]*User, error)
 	// FindTokenByUser attempts to retrieve a SpotifyToken for the given user ID
 	FindTokenByUser(userID uint) (*SpotifyToken, error)
 	// Save upserts the given user into the DB
@@ -65,6 +71,17 @@ func (s *UserService) UpsertUser(id, name, email, image, token, refresh string)
 	return user, s.r.SaveTokenForUser(user, token, refresh)
 }
 
+// FindActive users, who logged in or had their token refreshed within the given duration
+func (s *UserService) FindActive(within time.Duration) ([]*User, error) {
+	return s.r.FindActive(time.Now().Add(-within))
+}
+
+// SetDriftPolicy of the given user
+func (s *UserService) SetDriftPolicy(user *User, policy string) error {
+	user.DriftPolicy = policy
+	return s.r.Save(user)
+}
+
 // FindTokenForUser finds a stored spotify OAuth token for the given user
 func (s *UserService) FindTokenForUser(userID uint) (*SpotifyToken, error) {
 	return s.r.FindTokenByUser(userID)
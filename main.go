@@ -15,6 +15,7 @@ import (
 	"github.com/flexicon/spotimoods-go/internal/config"
 	"github.com/flexicon/spotimoods-go/internal/db"
 	"github.com/flexicon/spotimoods-go/internal/queue"
+	"github.com/flexicon/spotimoods-go/internal/scheduler"
 	"github.com/flexicon/spotimoods-go/internal/spotify"
 	"github.com/labstack/echo/v4"
 	"github.com/spf13/viper"
@@ -45,6 +46,11 @@ func main() {
 	go setupQueueListener(services)
 	go pingQueue(qs)
 
+	// Periodic jobs are only scheduled by the background worker
+	if viper.GetBool("worker") {
+		scheduler.New(services).Start()
+	}
+
 	// Setup web server if not running as a background worker
 	if !viper.GetBool("worker") {
 		e := echo.New()
@@ -56,7 +62,7 @@ func main() {
 	}
 
 	// Run worker until system interrupt signal is received
-	sigs := make(chan os.Signal)
+	sigs := make(chan os.Signal, 1)
 	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
 	<-sigs
 }